@@ -2,16 +2,32 @@ package incr
 
 import (
 	"fmt"
+	"math"
 	"sync"
 )
 
-func newAdjustHeightsHeap(maxHeightAllowed int) *adjustHeightsHeap {
+// newAdjustHeightsHeap returns a new adjust heights heap.
+//
+// If maxHeight is greater than zero, node heights are
+// limited to less than the max height.
+func newAdjustHeightsHeap(maxHeight int) *adjustHeightsHeap {
+	initialHeights := initialHeightsSize(maxHeight)
 	return &adjustHeightsHeap{
-		nodesByHeight:    make([]*queue[INode], maxHeightAllowed),
-		heightLowerBound: maxHeightAllowed + 1,
+		nodesByHeight:    make([]*queue[INode], initialHeights),
+		maxHeight:        maxHeight,
+		heightLowerBound: initialHeights,
 	}
 }
 
+// initialHeightsSize returns the number of height blocks to
+// preallocate for a given max height.
+func initialHeightsSize(maxHeight int) int {
+	if maxHeight > 0 && maxHeight < DefaultMaxHeight {
+		return maxHeight
+	}
+	return DefaultMaxHeight
+}
+
 // adjustHeightsHeap is a specialized heap that handles setting node heights
 // such that we don't recompute heights of nodes more than once.
 //
@@ -21,19 +37,34 @@ func newAdjustHeightsHeap(maxHeightAllowed int) *adjustHeightsHeap {
 // height above that, recursing through the children adding more as we
 // see them, preserving the invariant.
 type adjustHeightsHeap struct {
-	mu               sync.Mutex
-	nodesByHeight    []*queue[INode]
+	mu            sync.Mutex
+	nodesByHeight []*queue[INode]
+	// maxHeight is the optional limit on node heights, where
+	// zero indicates that heights are not limited.
+	maxHeight        int
 	numNodes         int
 	maxHeightSeen    int
 	heightLowerBound int
+	// numHeightsAdjusted is the total number of times a
+	// node's height has been raised by [adjustHeightsHeap.adjustHeights].
+	numHeightsAdjusted uint64
 }
 
 func (ah *adjustHeightsHeap) len() int {
 	return ah.numNodes
 }
 
+func (ah *adjustHeightsHeap) heightsAdjusted() uint64 {
+	ah.mu.Lock()
+	defer ah.mu.Unlock()
+	return ah.numHeightsAdjusted
+}
+
 func (ah *adjustHeightsHeap) maxHeightAllowed() int {
-	return len(ah.nodesByHeight) - 1
+	if ah.maxHeight > 0 {
+		return ah.maxHeight - 1
+	}
+	return math.MaxInt - 1
 }
 
 func (ah *adjustHeightsHeap) setHeight(node INode, height int) error {
@@ -47,6 +78,7 @@ func (ah *adjustHeightsHeap) adjustHeights(rh *recomputeHeap, originalChild, ori
 	rh.mu.Lock()
 	defer ah.mu.Unlock()
 	defer rh.mu.Unlock()
+	rh.foldPendingUnsafe()
 
 	ah.heightLowerBound = originalChild.Node().height
 	if err := ah.ensureHeightRequirementUnsafe(originalChild, originalParent, originalChild, originalParent); err != nil {
@@ -76,13 +108,14 @@ func (ah *adjustHeightsHeap) adjustHeights(rh *recomputeHeap, originalChild, ori
 }
 
 func (ah *adjustHeightsHeap) ensureHeightRequirementUnsafe(originalChild, originalParent, child, parent INode) error {
-	if originalParent.Node().id == child.Node().id {
+	if originalParent.Node() == child.Node() {
 		return fmt.Errorf("cycle detected at %v to %v", originalChild, originalParent)
 	}
 	if parent.Node().height >= child.Node().height {
 		// we set `child.height` after adding `child` to the heap, so that `child` goes
 		// in the heap with its pre-adjusted height.
 		ah.addUnsafe(child)
+		ah.numHeightsAdjusted++
 		if err := ah.setHeightUnsafe(child, parent.Node().height+1); err != nil {
 			return err
 		}
@@ -97,7 +130,7 @@ func (ah *adjustHeightsHeap) removeMinUnsafe() (node INode, ok bool) {
 	// NOTE (wc): we cannot start at heightLowerBound because of
 	// transient parallel recomputation issues. We use zero here to avoid
 	// locking the node metadata.
-	for x := 0; x <= ah.maxHeightSeen; x++ {
+	for x := 0; x <= ah.maxHeightSeen && x < len(ah.nodesByHeight); x++ {
 		if ah.nodesByHeight[x] != nil && ah.nodesByHeight[x].len() > 0 {
 			node, ok = ah.nodesByHeight[x].pop()
 			ah.heightLowerBound = x
@@ -115,6 +148,9 @@ func (ah *adjustHeightsHeap) addUnsafe(node INode) {
 	}
 	height := node.Node().height
 	node.Node().heightInAdjustHeightsHeap = height
+	if len(ah.nodesByHeight) <= height {
+		ah.nodesByHeight = append(ah.nodesByHeight, make([]*queue[INode], (height-len(ah.nodesByHeight))+1)...)
+	}
 	if ah.nodesByHeight[height] == nil {
 		ah.nodesByHeight[height] = new(queue[INode])
 	}
@@ -127,7 +163,7 @@ func (ah *adjustHeightsHeap) addUnsafe(node INode) {
 
 func (ah *adjustHeightsHeap) setHeightUnsafe(node INode, height int) error {
 	if height > ah.maxHeightAllowed() {
-		return fmt.Errorf("cannot set node height above %d", ah.maxHeightAllowed())
+		return fmt.Errorf("cannot set node height above %d, consider raising the graph max height with OptGraphMaxHeight", ah.maxHeightAllowed())
 	}
 	if height > ah.maxHeightSeen {
 		ah.maxHeightSeen = height
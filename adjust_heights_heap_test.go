@@ -85,3 +85,26 @@ func Test_adjustHeightsHeap_adjustHeights(t *testing.T) {
 	testutil.Error(t, err, "we should error on the original parent being beyond the maximum height")
 	testutil.Equal(t, 5, ahh.heightLowerBound, "we should still set the height lower bound on error")
 }
+
+func Test_adjustHeightsHeap_unbounded(t *testing.T) {
+	g := New()
+	ahh := newAdjustHeightsHeap(0)
+	testutil.Equal(t, DefaultMaxHeight, len(ahh.nodesByHeight))
+
+	n0 := newMockBareNodeWithHeight(g, 1)
+	err := ahh.setHeightUnsafe(n0, 2*DefaultMaxHeight)
+	testutil.NoError(t, err)
+	ahh.addUnsafe(n0)
+	testutil.Equal(t, 2*DefaultMaxHeight+1, len(ahh.nodesByHeight))
+	testutil.Equal(t, 1, ahh.len())
+
+	n1 := newMockBareNodeWithHeight(g, 3)
+	ahh.addUnsafe(n1)
+
+	out, ok := ahh.removeMinUnsafe()
+	testutil.Equal(t, true, ok)
+	testutil.Equal(t, n1.Node().ID(), out.Node().ID())
+	out, ok = ahh.removeMinUnsafe()
+	testutil.Equal(t, true, ok)
+	testutil.Equal(t, n0.Node().ID(), out.Node().ID())
+}
@@ -51,6 +51,7 @@ func (a *alwaysIncr[A]) Stale() bool {
 func (a *alwaysIncr[A]) Always() {}
 
 func (a *alwaysIncr[A]) Value() A {
+	a.n.checkRead(a)
 	return a.input.Value()
 }
 
@@ -0,0 +1,230 @@
+package incr
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Future is a value that will be available at some point in the future.
+type Future[T any] interface {
+	// Done returns a channel that is closed when the result is available.
+	Done() <-chan struct{}
+	// Result returns the result and is only valid after the channel
+	// returned by [Future.Done] is closed.
+	Result() (T, error)
+}
+
+// Go returns a future for the result of a given function, which is called
+// on a new goroutine.
+func Go[T any](ctx context.Context, fn func(context.Context) (T, error)) Future[T] {
+	f := &goFuture[T]{
+		done: make(chan struct{}),
+	}
+	go func() {
+		defer close(f.done)
+		f.value, f.err = fn(ctx)
+	}()
+	return f
+}
+
+type goFuture[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+func (f *goFuture[T]) Done() <-chan struct{} { return f.done }
+
+func (f *goFuture[T]) Result() (T, error) { return f.value, f.err }
+
+// Async returns a node that applies a given function that returns a future to the
+// value of an input node.
+//
+// When the input changes, the function is called to start the work, and stabilization
+// continues past the node with the last known value; the node reports that it is
+// pending with [AsyncIncr.Pending]. When the future completes, the node is marked stale
+// as with [Graph.SetStale], and the result will be applied, and the node's children recomputed,
+// on the next stabilization. If the future returns an error, the error is returned by
+// that stabilization.
+//
+// The context passed to the function is canceled if the input changes before the
+// future completes, and the result of the superseded future is discarded.
+//
+// Because futures complete on other goroutines, the graph must be created with
+// [OptGraphConcurrent] to interlock marking the node stale with stabilization, and
+// Async panics if it is not.
+func Async[A, B any](scope Scope, input Incr[A], fn func(context.Context, A) Future[B]) AsyncIncr[B] {
+	if !scope.scopeGraph().concurrent {
+		panic("incr; async nodes require a concurrent graph, see OptGraphConcurrent")
+	}
+	// the function is passed a context derived from the stabilization
+	// context that outlives the stabilization.
+	scope.scopeGraph().retainsContexts.Store(true)
+	return WithinScope(scope, &asyncIncr[A, B]{
+		n:     NewNode("async"),
+		input: input,
+		fn:    fn,
+	})
+}
+
+// AsyncIncr is a type that can represent an [Async] incremental.
+type AsyncIncr[B any] interface {
+	Incr[B]
+	// Pending returns if the node is waiting on a future to complete.
+	Pending() bool
+}
+
+var (
+	_ AsyncIncr[string] = (*asyncIncr[int, string])(nil)
+	_ IParents          = (*asyncIncr[int, string])(nil)
+	_ ICutoff           = (*asyncIncr[int, string])(nil)
+	_ IStabilize        = (*asyncIncr[int, string])(nil)
+	_ IStale            = (*asyncIncr[int, string])(nil)
+	_ releaser          = (*asyncIncr[int, string])(nil)
+	_ fmt.Stringer      = (*asyncIncr[int, string])(nil)
+)
+
+type asyncIncr[A, B any] struct {
+	n     *Node
+	input Incr[A]
+	fn    func(context.Context, A) Future[B]
+	value B
+
+	// mu interlocks the fields below with
+	// the goroutines waiting on futures.
+	mu         sync.Mutex
+	generation uint64
+	startedAt  uint64
+	cancel     context.CancelFunc
+	pending    bool
+	ready      bool
+	result     B
+	err        error
+}
+
+func (a *asyncIncr[A, B]) Parents() []INode {
+	return []INode{a.input}
+}
+
+func (a *asyncIncr[A, B]) Node() *Node { return a.n }
+
+func (a *asyncIncr[A, B]) Value() B {
+	a.n.checkRead(a)
+	return a.value
+}
+
+func (a *asyncIncr[A, B]) Stale() bool {
+	a.mu.Lock()
+	started := a.startedAt != 0
+	a.mu.Unlock()
+	return !started || a.n.recomputedAt == 0 || a.n.isStaleInRespectToParent()
+}
+
+func (a *asyncIncr[A, B]) Pending() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.pending
+}
+
+// Cutoff starts new work if the input has changed, and cuts off
+// the recomputation unless a future has completed.
+func (a *asyncIncr[A, B]) Cutoff(ctx context.Context) (bool, error) {
+	a.mu.Lock()
+	if a.startedAt == 0 || a.input.Node().changedAt > a.startedAt {
+		generation, futureCtx := a.start(ctx)
+		a.mu.Unlock()
+
+		// the function is called without the mutex held so
+		// that it can call methods of the node, e.g. Pending.
+		future := a.fn(futureCtx, a.input.Value())
+		go a.wait(generation, future)
+		return true, nil
+	}
+	ready := a.ready
+	a.mu.Unlock()
+	return !ready, nil
+}
+
+func (a *asyncIncr[A, B]) Stabilize(_ context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var zero B
+	err := a.err
+	if err == nil {
+		a.value = a.result
+	}
+	a.ready = false
+	a.pending = false
+	a.result = zero
+	a.err = nil
+	return err
+}
+
+func (a *asyncIncr[A, B]) String() string {
+	return a.n.String()
+}
+
+// start resets the node for new work, canceling any in-progress work, and
+// returns the generation and context of the new work. It must be called with
+// the mutex held.
+func (a *asyncIncr[A, B]) start(ctx context.Context) (uint64, context.Context) {
+	if a.cancel != nil {
+		a.cancel()
+	}
+	var zero B
+	a.generation++
+	a.startedAt = GraphForNode(a).stabilizationNum
+	a.pending = true
+	a.ready = false
+	a.result = zero
+	a.err = nil
+
+	// the work will outlive the stabilization so it should not
+	// be canceled when the stabilization context is.
+	futureCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	a.cancel = cancel
+	return a.generation, futureCtx
+}
+
+// release cancels any in-progress work and discards its result, such that
+// the work is started again if the node becomes necessary again.
+func (a *asyncIncr[A, B]) release() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.cancel != nil {
+		a.cancel()
+		a.cancel = nil
+	}
+	if !a.pending && !a.ready {
+		return
+	}
+	var zero B
+	a.generation++
+	a.startedAt = 0
+	a.pending = false
+	a.ready = false
+	a.result = zero
+	a.err = nil
+}
+
+func (a *asyncIncr[A, B]) wait(generation uint64, future Future[B]) {
+	<-future.Done()
+	result, err := future.Result()
+
+	a.mu.Lock()
+	if generation != a.generation {
+		a.mu.Unlock()
+		return
+	}
+	a.cancel()
+	a.cancel = nil
+	a.ready = true
+	a.result = result
+	a.err = err
+	a.mu.Unlock()
+
+	// the node may stop being necessary after the generation
+	// is checked, in which case the stale mark is dropped.
+	GraphForNode(a).setStaleIfNecessary(a)
+}
@@ -0,0 +1,271 @@
+package incr
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/wcharczuk/go-incr/testutil"
+)
+
+type testFuture[T any] struct {
+	ctx   context.Context
+	done  chan struct{}
+	value T
+	err   error
+}
+
+func (f *testFuture[T]) Done() <-chan struct{} { return f.done }
+
+func (f *testFuture[T]) Result() (T, error) { return f.value, f.err }
+
+func (f *testFuture[T]) complete(value T, err error) {
+	f.value = value
+	f.err = err
+	close(f.done)
+}
+
+func waitForSetStale(t *testing.T, setStale chan INode) {
+	t.Helper()
+	select {
+	case <-setStale:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for node to be marked stale")
+	}
+}
+
+func Test_Async(t *testing.T) {
+	ctx := testContext()
+	g := New(OptGraphConcurrent(true))
+	setStale := make(chan INode, 16)
+	g.OnSetStale(func(n INode) {
+		setStale <- n
+	})
+
+	v := Var(g, "foo")
+	other := Var(g, "other")
+	futures := make(chan *testFuture[string], 16)
+	a := Async(g, v, func(ctx context.Context, vv string) Future[string] {
+		f := &testFuture[string]{ctx: ctx, done: make(chan struct{})}
+		futures <- f
+		return f
+	})
+	var mapCalls int
+	m := Map2(g, a, other, func(av, ov string) string {
+		mapCalls++
+		return av + "-" + ov
+	})
+	om := MustObserve(g, m)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, true, a.Pending())
+	testutil.Equal(t, "", a.Value())
+	testutil.Equal(t, "-other", om.Value())
+	testutil.Equal(t, 1, mapCalls)
+
+	// stabilization should not wait on the pending future.
+	other.Set("changed")
+	<-setStale
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, true, a.Pending())
+	testutil.Equal(t, "-changed", om.Value())
+	testutil.Equal(t, 2, mapCalls)
+
+	f := <-futures
+	f.complete("foo-result", nil)
+	waitForSetStale(t, setStale)
+
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, false, a.Pending())
+	testutil.Equal(t, "foo-result", a.Value())
+	testutil.Equal(t, "foo-result-changed", om.Value())
+	testutil.Equal(t, 3, mapCalls)
+}
+
+func Test_Async_superseded(t *testing.T) {
+	ctx := testContext()
+	g := New(OptGraphConcurrent(true))
+	setStale := make(chan INode, 16)
+	g.OnSetStale(func(n INode) {
+		setStale <- n
+	})
+
+	v := Var(g, "foo")
+	futures := make(chan *testFuture[string], 16)
+	a := Async(g, v, func(ctx context.Context, vv string) Future[string] {
+		f := &testFuture[string]{ctx: ctx, done: make(chan struct{})}
+		futures <- f
+		return f
+	})
+	oa := MustObserve(g, a)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	first := <-futures
+
+	v.Set("bar")
+	<-setStale
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	second := <-futures
+	testutil.NotNil(t, first.ctx.Err(), "the superseded future should be canceled")
+	testutil.Nil(t, second.ctx.Err())
+
+	first.complete("foo-result", nil)
+	second.complete("bar-result", nil)
+	waitForSetStale(t, setStale)
+
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, false, a.Pending())
+	testutil.Equal(t, "bar-result", oa.Value())
+	testutil.Equal(t, 0, len(setStale), "the superseded future should not mark the node stale")
+}
+
+func Test_Async_error(t *testing.T) {
+	ctx := testContext()
+	g := New(OptGraphConcurrent(true))
+	setStale := make(chan INode, 16)
+	g.OnSetStale(func(n INode) {
+		setStale <- n
+	})
+
+	v := Var(g, "foo")
+	a := Async(g, v, func(ctx context.Context, vv string) Future[string] {
+		return Go(ctx, func(_ context.Context) (string, error) {
+			return "", fmt.Errorf("this is only a test")
+		})
+	})
+	oa := MustObserve(g, a)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	waitForSetStale(t, setStale)
+
+	err = g.Stabilize(ctx)
+	testutil.Error(t, err)
+	testutil.Equal(t, false, a.Pending())
+	testutil.Equal(t, "", oa.Value())
+}
+
+func Test_Async_unobserved(t *testing.T) {
+	ctx := testContext()
+	g := New(OptGraphConcurrent(true))
+	setStale := make(chan INode, 16)
+	g.OnSetStale(func(n INode) {
+		setStale <- n
+	})
+
+	v := Var(g, "foo")
+	futures := make(chan *testFuture[string], 16)
+	a := Async(g, v, func(ctx context.Context, vv string) Future[string] {
+		f := &testFuture[string]{ctx: ctx, done: make(chan struct{})}
+		futures <- f
+		return f
+	})
+	oa := MustObserve(g, a)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	first := <-futures
+
+	oa.Unobserve(ctx)
+	testutil.NotNil(t, first.ctx.Err(), "the future should be canceled when the node is unobserved")
+	testutil.Equal(t, false, a.Pending())
+
+	first.complete("foo-result", nil)
+	testutil.Equal(t, false, g.recomputeHeap.has(a))
+
+	// a stale mark that races with the node becoming unnecessary should be dropped.
+	g.setStaleIfNecessary(a)
+	<-setStale
+	testutil.Equal(t, false, g.recomputeHeap.has(a))
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 0, len(futures))
+	testutil.Equal(t, false, a.Pending())
+
+	oa = MustObserve(g, a)
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	second := <-futures
+	testutil.Nil(t, second.ctx.Err())
+	testutil.Equal(t, true, a.Pending())
+
+	second.complete("bar-result", nil)
+	waitForSetStale(t, setStale)
+
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "bar-result", oa.Value())
+}
+
+func Test_Async_pendingWithinFn(t *testing.T) {
+	ctx := testContext()
+	g := New(OptGraphConcurrent(true))
+	setStale := make(chan INode, 16)
+	g.OnSetStale(func(n INode) {
+		setStale <- n
+	})
+
+	v := Var(g, "foo")
+	var a AsyncIncr[string]
+	var pendingWithinFn bool
+	a = Async(g, v, func(ctx context.Context, vv string) Future[string] {
+		pendingWithinFn = a.Pending()
+		return Go(ctx, func(_ context.Context) (string, error) {
+			return vv + "-result", nil
+		})
+	})
+	oa := MustObserve(g, a)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- g.Stabilize(ctx)
+	}()
+	select {
+	case err := <-done:
+		testutil.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("stabilization did not complete; the function should be able to call methods of the node")
+	}
+	testutil.Equal(t, true, pendingWithinFn)
+	waitForSetStale(t, setStale)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "foo-result", oa.Value())
+}
+
+func Test_Async_notConcurrent(t *testing.T) {
+	g := New()
+	v := Var(g, "foo")
+
+	var recovered any
+	func() {
+		defer func() {
+			recovered = recover()
+		}()
+		_ = Async(g, v, func(ctx context.Context, vv string) Future[string] {
+			return Go(ctx, func(_ context.Context) (string, error) {
+				return vv, nil
+			})
+		})
+	}()
+	testutil.NotNil(t, recovered)
+}
+
+func Test_Go(t *testing.T) {
+	ctx := testContext()
+	f := Go(ctx, func(_ context.Context) (string, error) {
+		return "hello", nil
+	})
+	<-f.Done()
+	value, err := f.Result()
+	testutil.NoError(t, err)
+	testutil.Equal(t, "hello", value)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"sync"
 	"testing"
 )
 
@@ -127,6 +128,30 @@ func Benchmark_createGraphpreallocateNodes__customIdentifierProvider_8192(b *tes
 	benchmarkCreateGraph(8192, true, b)
 }
 
+func Benchmark_createGraph_16384(b *testing.B) {
+	benchmarkCreateGraph(16384, false, b)
+}
+
+func Benchmark_createGraph_preallocateNodes_16384(b *testing.B) {
+	benchmarkCreateGraph(16384, true, b)
+}
+
+func Benchmark_createGraph_customIdentifierProvider_16384(b *testing.B) {
+	b.Cleanup(func() {
+		SetIdentifierProvider(cryptoRandIdentifierProvider)
+	})
+	SetIdentifierProvider(counterIdentifierProvider)
+	benchmarkCreateGraph(16384, false, b)
+}
+
+func Benchmark_createGraph_preallocateNodes_customIdentifierProvider_16384(b *testing.B) {
+	b.Cleanup(func() {
+		SetIdentifierProvider(cryptoRandIdentifierProvider)
+	})
+	SetIdentifierProvider(counterIdentifierProvider)
+	benchmarkCreateGraph(16384, true, b)
+}
+
 func Benchmark_Stabilize_withPreInitialize_512(b *testing.B) {
 	benchmarkSize(512, b)
 }
@@ -175,6 +200,38 @@ func Benchmark_ParallelStabilize_withPreInitialize_16384(b *testing.B) {
 	benchmarkParallelSize(16384, b)
 }
 
+func Benchmark_ParallelStabilize_goroutinePerNode_withPreInitialize_512(b *testing.B) {
+	benchmarkGoroutinePerNodeParallelSize(512, b)
+}
+
+func Benchmark_ParallelStabilize_goroutinePerNode_withPreInitialize_1024(b *testing.B) {
+	benchmarkGoroutinePerNodeParallelSize(1024, b)
+}
+
+func Benchmark_ParallelStabilize_goroutinePerNode_withPreInitialize_2048(b *testing.B) {
+	benchmarkGoroutinePerNodeParallelSize(2048, b)
+}
+
+func Benchmark_ParallelStabilize_goroutinePerNode_withPreInitialize_4096(b *testing.B) {
+	benchmarkGoroutinePerNodeParallelSize(4096, b)
+}
+
+func Benchmark_ParallelStabilize_goroutinePerNode_withPreInitialize_8192(b *testing.B) {
+	benchmarkGoroutinePerNodeParallelSize(8192, b)
+}
+
+func Benchmark_ParallelStabilize_goroutinePerNode_withPreInitialize_16384(b *testing.B) {
+	benchmarkGoroutinePerNodeParallelSize(16384, b)
+}
+
+func Benchmark_Stabilize_steadyState_1024(b *testing.B) {
+	benchmarkSteadyState(1024, b)
+}
+
+func Benchmark_Stabilize_steadyState_8192(b *testing.B) {
+	benchmarkSteadyState(8192, b)
+}
+
 func Benchmark_Stabilize_recombinant_64(b *testing.B) {
 	benchmarkRecombinantSize(64, b)
 }
@@ -207,6 +264,18 @@ func Benchmark_ParallelStabilize_recombinant_512(b *testing.B) {
 	benchmarkParallelRecombinantSize(512, b)
 }
 
+func Benchmark_DataflowStabilize_withPreInitialize_1024(b *testing.B) {
+	benchmarkDataflowSize(1024, b)
+}
+
+func Benchmark_DataflowStabilize_withPreInitialize_8192(b *testing.B) {
+	benchmarkDataflowSize(8192, b)
+}
+
+func Benchmark_DataflowStabilize_recombinant_256(b *testing.B) {
+	benchmarkDataflowRecombinantSize(256, b)
+}
+
 func Benchmark_Stabilize_deep_2_32(b *testing.B) {
 	benchmarkDepth(2, 32, b)
 }
@@ -247,6 +316,10 @@ func Benchmark_Stabilize_nestedBinds_128(b *testing.B) {
 	benchmarkNestedBinds(128, b)
 }
 
+func Benchmark_Stabilize_bindChurnHub_50000(b *testing.B) {
+	benchmarkBindChurnHub(50000, b)
+}
+
 func longer(a, b *string) *string {
 	if a == nil && b == nil {
 		return nil
@@ -265,8 +338,7 @@ func longer(a, b *string) *string {
 
 func ref[A any](v A) *A { return &v }
 
-func makeBenchmarkGraph(size int, preallocate bool) (*Graph, []Incr[*string]) {
-	var options []GraphOption
+func makeBenchmarkGraph(size int, preallocate bool, options ...GraphOption) (*Graph, []Incr[*string]) {
 	if preallocate {
 		options = append(options, OptGraphPreallocateNodesSize(size<<1))
 	}
@@ -386,6 +458,131 @@ func benchmarkParallelSize(size int, b *testing.B) {
 	}
 }
 
+func benchmarkDataflowSize(size int, b *testing.B) {
+	graph, nodes := makeBenchmarkGraph(size, false /*preallocate*/)
+	ctx := testContext()
+	b.ResetTimer()
+	var err error
+	for n := 0; n < b.N; n++ {
+		err = graph.DataflowStabilize(ctx)
+		if err != nil {
+			b.Fatal(err)
+		}
+		graph.SetStale(nodes[rand.Intn(size)])
+		err = graph.DataflowStabilize(ctx)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkGoroutinePerNodeParallelSize(size int, b *testing.B) {
+	graph, nodes := makeBenchmarkGraph(size, false /*preallocate*/)
+	ctx := testContext()
+	b.ResetTimer()
+	var err error
+	for n := 0; n < b.N; n++ {
+		err = goroutinePerNodeParallelStabilize(ctx, graph)
+		if err != nil {
+			b.Fatal(err)
+		}
+		graph.SetStale(nodes[rand.Intn(size)])
+		err = goroutinePerNodeParallelStabilize(ctx, graph)
+		if err != nil {
+			b.Fatal(err)
+		}
+		graph.SetStale(nodes[rand.Intn(size)])
+		err = goroutinePerNodeParallelStabilize(ctx, graph)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// goroutinePerNodeParallelStabilize is the parallel stabilization that starts a goroutine
+// for each node in a height block, and is used as a baseline for [Graph.ParallelStabilize].
+func goroutinePerNodeParallelStabilize(ctx context.Context, graph *Graph) (err error) {
+	if err = graph.ensureNotStabilizing(ctx); err != nil {
+		return
+	}
+	ctx = graph.stabilizeStart(ctx)
+	defer func() {
+		graph.stabilizeEnd(ctx, err)
+	}()
+
+	var iter recomputeHeapListIter
+	for graph.recomputeHeap.len() > 0 {
+		graph.recomputeHeap.removeMinHeightIter(&iter)
+		var errOnce sync.Once
+		sem := make(chan INode, graph.parallelism)
+		wg := new(sync.WaitGroup)
+		for n, ok := iter.Next(); ok; n, ok = iter.Next() {
+			sem <- n
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if workErr := graph.recompute(ctx, <-sem, true); workErr != nil {
+					errOnce.Do(func() {
+						err = workErr
+					})
+				}
+			}()
+		}
+		wg.Wait()
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func benchmarkSteadyState(size int, b *testing.B) {
+	graph, vars := makeBenchmarkSteadyStateGraph(size)
+	ctx := context.Background()
+	values := []*string{ref("foo"), ref("bar")}
+	var index int
+	stabilize := func() {
+		vars[index%size].Set(values[index%2])
+		index++
+		if err := graph.Stabilize(ctx); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if allocs := testing.AllocsPerRun(100, stabilize); allocs > 0 {
+		b.Fatalf("expected steady state stabilization to not allocate, allocated %v times per run", allocs)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		stabilize()
+	}
+}
+
+// makeBenchmarkSteadyStateGraph returns a benchmark graph that has been stabilized,
+// with an always and cutoff node and update handlers, that reuses its stabilization
+// context, as well as the vars of the graph.
+func makeBenchmarkSteadyStateGraph(size int) (*Graph, []VarIncr[*string]) {
+	graph, nodes := makeBenchmarkGraph(size, false /*preallocate*/, OptGraphReuseStabilizationContext(true))
+	vars := make([]VarIncr[*string], size)
+	for x := 0; x < size; x++ {
+		vars[x] = nodes[x].(VarIncr[*string])
+	}
+	root := nodes[len(nodes)-1]
+	cutoff := Cutoff(graph, root, func(previous, current *string) bool {
+		return previous == current
+	})
+	always := Always(graph, cutoff)
+	var updates int
+	o := MustObserve(graph, always)
+	o.OnUpdate(func(_ context.Context, _ *string) {
+		updates++
+	})
+	if err := graph.Stabilize(context.Background()); err != nil {
+		panic(err)
+	}
+	return graph, vars
+}
+
 func benchmarkRecombinantSize(size int, b *testing.B) {
 	graph, input, observer := makeBenchmarkRecombinantGraph(size)
 	ctx := testContext()
@@ -450,6 +647,30 @@ func benchmarkParallelRecombinantSize(size int, b *testing.B) {
 	}
 }
 
+func benchmarkDataflowRecombinantSize(size int, b *testing.B) {
+	graph, input, observer := makeBenchmarkRecombinantGraph(size)
+	ctx := testContext()
+	b.ResetTimer()
+	var err error
+	for n := 0; n < b.N; n++ {
+		err = graph.DataflowStabilize(ctx)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if observer.Value() == nil {
+			b.Fail()
+		}
+		graph.SetStale(input)
+		err = graph.DataflowStabilize(ctx)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if observer.Value() == nil {
+			b.Fail()
+		}
+	}
+}
+
 func benchmarkDepth(width, depth int, b *testing.B) {
 	graph := New(
 		OptGraphMaxHeight(1024),
@@ -567,3 +788,36 @@ func makeNestedBindGraph(g *Graph, depth int, bindControl VarIncr[int]) ObserveI
 	om := MustObserve(g, m)
 	return om
 }
+
+// benchmarkBindChurnHub benchmarks binds that create and tear down right-hand side nodes
+// that are children of a "hub" node, which has a given number of other (static) children.
+func benchmarkBindChurnHub(children int, b *testing.B) {
+	ctx := testContext()
+	graph := New()
+	hub := Var(graph, 1)
+	for x := 0; x < children; x++ {
+		_ = MustObserve(graph, Map(graph, hub, func(v int) int { return v + 1 }))
+	}
+
+	const binds = 64
+	bindControl := Var(graph, 0)
+	observers := make([]ObserveIncr[int], binds)
+	for x := 0; x < binds; x++ {
+		observers[x] = MustObserve(graph, Bind(graph, bindControl, func(bs Scope, which int) Incr[int] {
+			return Map(bs, hub, func(v int) int { return v + which })
+		}))
+	}
+	if err := graph.Stabilize(ctx); err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		bindControl.Set(n + 1)
+		if err := graph.Stabilize(ctx); err != nil {
+			b.Fatal(err)
+		}
+		if observers[0].Value() != n+2 {
+			b.Fatalf("expected %d, got %d", n+2, observers[0].Value())
+		}
+	}
+}
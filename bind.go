@@ -3,6 +3,7 @@ package incr
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 )
 
 // Bind lets you swap out an entire subgraph of a computation based
@@ -76,6 +77,20 @@ type BindIncr[A any] interface {
 	IShouldBeInvalidated
 	IBindMain
 	fmt.Stringer
+
+	// OnScopeCreated registers a handler that is called each time the bind
+	// function returns successfully and creates a new right-hand-side scope.
+	//
+	// Handlers are called during stabilization on the goroutine recomputing
+	// the bind, and must not call methods on the graph that take locks.
+	OnScopeCreated(func(Scope))
+	// OnScopeDisposed registers a handler that is called each time a
+	// right-hand-side scope is torn down, either because the bind swapped
+	// to a new scope or because the bind itself was invalidated.
+	//
+	// Handlers are called after the nodes of the scope are invalidated and
+	// after any functions registered with [Scope.Defer] in the scope.
+	OnScopeDisposed(func(Scope))
 }
 
 // IBindMain holds the methods specific to the bind main node.
@@ -109,11 +124,37 @@ type bind[A, B any] struct {
 	fn        BindContextFunc[A, B]
 	main      *bindMainIncr[A, B]
 	lhsChange *bindLeftChangeIncr[A, B]
+
+	// building is true while the bind function is building the current scope.
+	building bool
+	// hasScope is true if the bind function has returned successfully
+	// and the resulting scope has not been disposed yet.
+	hasScope bool
+	// deferred holds the functions registered with [Scope.Defer]
+	// while building the current scope.
+	deferred        []func()
+	onScopeCreated  []func(Scope)
+	onScopeDisposed []func(Scope)
+
+	// scopes is set for binds that keep a right-hand-side scope per
+	// input value, e.g. [BindKeyed], and replaces the default behavior
+	// of rebuilding a single scope each time the input changes.
+	scopes bindScopes[A]
+}
+
+// bindScopes manages the right-hand-side scopes of a bind.
+type bindScopes[A any] interface {
+	// activate makes the scope for a given input value the current
+	// right-hand-side of the bind, building it if it doesn't exist yet.
+	activate(context.Context, A) error
+	// disposeAll disposes the current scope and any inactive scopes.
+	disposeAll()
 }
 
 func (b *bind[A, B]) isTopScope() bool       { return false }
 func (b *bind[A, B]) isScopeValid() bool     { return b.main.Node().valid }
 func (b *bind[A, B]) isScopeNecessary() bool { return b.main.Node().isNecessary() }
+func (b *bind[A, B]) isScopeBuilding() bool  { return b.building }
 func (b *bind[A, B]) scopeGraph() *Graph     { return b.graph }
 func (b *bind[A, B]) scopeHeight() int       { return b.lhs.Node().height }
 
@@ -121,10 +162,38 @@ func (b *bind[A, B]) addScopeNode(n INode) {
 	b.rhsNodes = append(b.rhsNodes, n)
 }
 
+func (b *bind[A, B]) Defer(fn func()) {
+	b.deferred = append(b.deferred, fn)
+}
+
 func (b *bind[A, B]) String() string {
 	return fmt.Sprintf("{%v}", b.main)
 }
 
+func (b *bind[A, B]) scopeCreated(scope Scope) {
+	atomic.AddUint64(&b.graph.numBindScopesCreated, 1)
+	for _, handler := range b.onScopeCreated {
+		handler(scope)
+	}
+}
+
+// scopeDisposed calls the given deferred functions in reverse order of
+// registration, matching the semantics of a go defer, and then
+// the disposed handlers.
+func (b *bind[A, B]) scopeDisposed(scope Scope, deferred []func()) {
+	atomic.AddUint64(&b.graph.numBindScopesDestroyed, 1)
+	runDeferred(deferred)
+	for _, handler := range b.onScopeDisposed {
+		handler(scope)
+	}
+}
+
+func runDeferred(deferred []func()) {
+	for index := len(deferred) - 1; index >= 0; index-- {
+		deferred[index]()
+	}
+}
+
 type bindMainIncr[A, B any] struct {
 	n       *Node
 	bind    *bind[A, B]
@@ -147,6 +216,7 @@ func (b *bindMainIncr[A, B]) ShouldBeInvalidated() bool {
 func (b *bindMainIncr[A, B]) Node() *Node { return b.n }
 
 func (b *bindMainIncr[A, B]) Value() (output B) {
+	b.n.checkRead(b)
 	return b.value
 }
 
@@ -160,10 +230,28 @@ func (b *bindMainIncr[A, B]) Stabilize(ctx context.Context) error {
 	return nil
 }
 
+func (b *bindMainIncr[A, B]) OnScopeCreated(fn func(Scope)) {
+	b.bind.onScopeCreated = append(b.bind.onScopeCreated, fn)
+}
+
+func (b *bindMainIncr[A, B]) OnScopeDisposed(fn func(Scope)) {
+	b.bind.onScopeDisposed = append(b.bind.onScopeDisposed, fn)
+}
+
 func (b *bindMainIncr[A, B]) Invalidate() {
+	if b.bind.scopes != nil {
+		b.bind.scopes.disposeAll()
+		return
+	}
 	for _, n := range b.bind.rhsNodes {
 		GraphForNode(b).invalidateNode(n)
 	}
+	if b.bind.hasScope {
+		deferred := b.bind.deferred
+		b.bind.hasScope = false
+		b.bind.deferred = nil
+		b.bind.scopeDisposed(b.bind, deferred)
+	}
 }
 
 func (b *bindMainIncr[A, B]) String() string {
@@ -191,11 +279,22 @@ func (b *bindLeftChangeIncr[A, B]) RightScopeNodes() []INode {
 }
 
 func (b *bindLeftChangeIncr[A, B]) Stabilize(ctx context.Context) (err error) {
+	if b.bind.scopes != nil {
+		return b.bind.scopes.activate(ctx, b.bind.lhs.Value())
+	}
 	oldRightNodes := b.bind.rhsNodes
 	oldRhs := b.bind.rhs
+	oldParents := b.bind.main.parents
+	oldDeferred := b.bind.deferred
+	hadScope := b.bind.hasScope
 	b.bind.rhsNodes = nil
+	b.bind.deferred = nil
+	b.bind.building = true
 	b.bind.rhs, err = b.bind.fn(ctx, b.bind, b.bind.lhs.Value())
+	b.bind.building = false
 	if err != nil {
+		b.abandonScope(oldRhs, oldRightNodes, oldParents, oldDeferred)
+		GraphForNode(b).propagateInvalidity()
 		return
 	}
 
@@ -206,6 +305,9 @@ func (b *bindLeftChangeIncr[A, B]) Stabilize(ctx context.Context) (err error) {
 	}
 
 	if err = GraphForNode(b).changeParent(b.bind.main, oldRhs, b.bind.rhs); err != nil {
+		GraphForNode(b).unchangeParent(b.bind.main, oldRhs, b.bind.rhs)
+		b.abandonScope(oldRhs, oldRightNodes, oldParents, oldDeferred)
+		GraphForNode(b).propagateInvalidity()
 		return err
 	}
 	if oldRhs != nil {
@@ -223,9 +325,29 @@ func (b *bindLeftChangeIncr[A, B]) Stabilize(ctx context.Context) (err error) {
 		// }
 	}
 	GraphForNode(b).propagateInvalidity()
+	if hadScope {
+		b.bind.scopeDisposed(b.bind, oldDeferred)
+	}
+	b.bind.hasScope = true
+	b.bind.scopeCreated(b.bind)
 	return nil
 }
 
+// abandonScope discards a scope that failed to build or to be linked to the bind
+// and will never be used, invalidating its nodes and releasing anything it deferred,
+// and makes the previous scope current again such that it's disposed as usual.
+func (b *bindLeftChangeIncr[A, B]) abandonScope(oldRhs Incr[B], oldRightNodes, oldParents []INode, oldDeferred []func()) {
+	nodes, deferred := b.bind.rhsNodes, b.bind.deferred
+	b.bind.rhs = oldRhs
+	b.bind.rhsNodes = oldRightNodes
+	b.bind.main.parents = oldParents
+	b.bind.deferred = oldDeferred
+	for _, n := range nodes {
+		GraphForNode(b).invalidateNode(n)
+	}
+	runDeferred(deferred)
+}
+
 func (b *bindLeftChangeIncr[A, B]) String() string {
 	return b.n.String()
 }
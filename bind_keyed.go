@@ -0,0 +1,288 @@
+package incr
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// BindKeyed is like [Bind] but keeps the right-hand-side scope built for each
+// input value, or key, around after the input changes.
+//
+// When the input changes, the current scope is parked rather than invalidated.
+// If the input later changes back to the key of a parked scope, that scope is
+// revived without calling the bind function again, and the nodes within it are
+// only recomputed if their own inputs changed while the scope was parked.
+//
+// Parked scopes are disposed, which invalidates their nodes and calls any
+// functions registered with [Scope.Defer], when they're evicted as described by
+// [BindKeyedOptions], or when the bind itself is invalidated.
+func BindKeyed[K comparable, A any](scope Scope, input Incr[K], fn BindFunc[K, A], opts ...BindKeyedOption) BindIncr[A] {
+	return BindKeyedContext(scope, input, func(_ context.Context, bs Scope, key K) (Incr[A], error) {
+		return fn(bs, key), nil
+	}, opts...)
+}
+
+// BindKeyedContext is like [BindKeyed] but allows the bind delegate to take a context and return an error.
+func BindKeyedContext[K comparable, A any](scope Scope, input Incr[K], fn BindContextFunc[K, A], opts ...BindKeyedOption) BindIncr[A] {
+	var options BindKeyedOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	bindMain := BindContext(scope, input, fn).(*bindMainIncr[K, A])
+	bindMain.bind.scopes = &bindKeyed[K, A]{
+		bind:    bindMain.bind,
+		options: options,
+		byKey:   make(map[K]*bindKeyedScope[K, A]),
+	}
+	return bindMain
+}
+
+// BindKeyedOptions are options for [BindKeyed].
+type BindKeyedOptions struct {
+	// MaxParked is the maximum number of parked scopes to keep, after which
+	// the least recently used scope is evicted.
+	//
+	// A value of zero (the default) means there is no limit.
+	MaxParked int
+	// ParkedTTL is how long a scope may stay parked before it's evicted.
+	//
+	// Expired scopes are evicted the next time the bind's input changes. A
+	// value of zero (the default) means scopes do not expire.
+	ParkedTTL time.Duration
+}
+
+// BindKeyedOption mutates BindKeyedOptions.
+type BindKeyedOption func(*BindKeyedOptions)
+
+// OptBindKeyedMaxParked sets the maximum number of parked scopes a keyed bind will keep.
+func OptBindKeyedMaxParked(maxParked int) BindKeyedOption {
+	return func(o *BindKeyedOptions) {
+		o.MaxParked = maxParked
+	}
+}
+
+// OptBindKeyedParkedTTL sets how long a keyed bind will keep a scope parked.
+func OptBindKeyedParkedTTL(ttl time.Duration) BindKeyedOption {
+	return func(o *BindKeyedOptions) {
+		o.ParkedTTL = ttl
+	}
+}
+
+var (
+	_ bindScopes[string] = (*bindKeyed[string, bool])(nil)
+	_ Scope              = (*bindKeyedScope[string, bool])(nil)
+)
+
+// bindKeyed implements bindScopes for a keyed bind.
+type bindKeyed[K comparable, A any] struct {
+	bind    *bind[K, A]
+	options BindKeyedOptions
+	current *bindKeyedScope[K, A]
+	// parked holds the parked scopes in the order they were parked,
+	// such that the least recently used scope is first.
+	parked []*bindKeyedScope[K, A]
+	byKey  map[K]*bindKeyedScope[K, A]
+}
+
+func (bk *bindKeyed[K, A]) activate(ctx context.Context, key K) error {
+	b := bk.bind
+	previous := bk.current
+	if previous != nil && previous.key == key {
+		return nil
+	}
+	scope, revived := bk.byKey[key]
+	parkedIndex := -1
+	var scopeState []parkedNodeState
+	if revived {
+		parkedIndex = slices.Index(bk.parked, scope)
+		scopeState = scope.recomputeState()
+		bk.unpark(scope)
+	} else {
+		scope = &bindKeyedScope[K, A]{keyed: bk, key: key}
+		scope.building = true
+		rhs, err := b.fn(ctx, scope, key)
+		scope.building = false
+		if err != nil {
+			bk.abandon(scope)
+			return err
+		}
+		scope.rhs = rhs
+	}
+
+	var previousState []parkedNodeState
+	if previous != nil {
+		previousState = previous.recomputeState()
+	}
+	oldRhs, oldRhsNodes, oldParents := b.rhs, b.rhsNodes, b.main.parents
+	bk.current = scope
+	b.rhs = scope.rhs
+	b.rhsNodes = scope.nodes
+	if b.rhs != nil {
+		b.main.parents = []INode{b.lhsChange, b.rhs}
+	} else {
+		b.main.parents = []INode{b.lhsChange}
+	}
+	if err := b.graph.changeParent(b.main, oldRhs, b.rhs); err != nil {
+		// keep the previous scope current such that it's disposed with the bind.
+		bk.current = previous
+		b.rhs, b.rhsNodes, b.main.parents = oldRhs, oldRhsNodes, oldParents
+		b.graph.unchangeParent(b.main, oldRhs, scope.rhs)
+		if revived {
+			scope.restoreRecomputeState(scopeState)
+			bk.repark(scope, parkedIndex)
+		} else {
+			bk.abandon(scope)
+		}
+		b.graph.propagateInvalidity()
+		return err
+	}
+	if !revived {
+		b.scopeCreated(scope)
+	}
+	if previous != nil {
+		previous.restoreRecomputeState(previousState)
+		previous.parkedAt = b.graph.stabilizationStarted
+		bk.parked = append(bk.parked, previous)
+		bk.byKey[previous.key] = previous
+	}
+	bk.evict(b.graph.stabilizationStarted)
+	b.graph.propagateInvalidity()
+	return nil
+}
+
+// abandon invalidates the nodes of a scope that failed to build or to be linked
+// to the bind and will never be used, and releases anything it deferred.
+func (bk *bindKeyed[K, A]) abandon(scope *bindKeyedScope[K, A]) {
+	scope.disposed = true
+	for _, n := range scope.nodes {
+		bk.bind.graph.invalidateNode(n)
+	}
+	runDeferred(scope.deferred)
+	scope.deferred = nil
+}
+
+// repark parks a revived scope again at its previous position.
+func (bk *bindKeyed[K, A]) repark(scope *bindKeyedScope[K, A], index int) {
+	if index < 0 || index > len(bk.parked) {
+		index = len(bk.parked)
+	}
+	bk.parked = slices.Insert(bk.parked, index, scope)
+	bk.byKey[scope.key] = scope
+}
+
+func (bk *bindKeyed[K, A]) unpark(scope *bindKeyedScope[K, A]) {
+	delete(bk.byKey, scope.key)
+	if index := slices.Index(bk.parked, scope); index >= 0 {
+		bk.parked = slices.Delete(bk.parked, index, index+1)
+	}
+}
+
+// evict disposes parked scopes, least recently used first, while there are more
+// than the maximum number of parked scopes or the scopes have expired.
+func (bk *bindKeyed[K, A]) evict(now time.Time) {
+	for len(bk.parked) > 0 {
+		oldest := bk.parked[0]
+		overLimit := bk.options.MaxParked > 0 && len(bk.parked) > bk.options.MaxParked
+		expired := bk.options.ParkedTTL > 0 && now.Sub(oldest.parkedAt) > bk.options.ParkedTTL
+		if !overLimit && !expired {
+			return
+		}
+		bk.unpark(oldest)
+		bk.dispose(oldest)
+	}
+}
+
+func (bk *bindKeyed[K, A]) disposeAll() {
+	if bk.current != nil {
+		current := bk.current
+		bk.current = nil
+		bk.dispose(current)
+	}
+	parked := bk.parked
+	bk.parked = nil
+	clear(bk.byKey)
+	for _, scope := range parked {
+		bk.dispose(scope)
+	}
+}
+
+func (bk *bindKeyed[K, A]) dispose(scope *bindKeyedScope[K, A]) {
+	scope.disposed = true
+	for _, n := range scope.nodes {
+		bk.bind.graph.invalidateNode(n)
+	}
+	deferred := scope.deferred
+	scope.deferred = nil
+	bk.bind.scopeDisposed(scope, deferred)
+}
+
+// bindKeyedScope is the right-hand-side scope of a keyed bind for a single key.
+type bindKeyedScope[K comparable, A any] struct {
+	keyed    *bindKeyed[K, A]
+	key      K
+	rhs      Incr[A]
+	nodes    []INode
+	deferred []func()
+	parkedAt time.Time
+	disposed bool
+	building bool
+}
+
+// parkedNodeState is the recompute state of a node in a parked scope.
+type parkedNodeState struct {
+	changedAt    uint64
+	recomputedAt uint64
+}
+
+// recomputeState returns the recompute state of the nodes in the scope.
+func (s *bindKeyedScope[K, A]) recomputeState() []parkedNodeState {
+	state := make([]parkedNodeState, len(s.nodes))
+	for index, n := range s.nodes {
+		state[index] = parkedNodeState{
+			changedAt:    n.Node().changedAt,
+			recomputedAt: n.Node().recomputedAt,
+		}
+	}
+	return state
+}
+
+// restoreRecomputeState restores the recompute state of the nodes in the
+// scope that were removed from the graph as the scope was parked, such that
+// they're only recomputed when the scope is revived if they're stale.
+func (s *bindKeyedScope[K, A]) restoreRecomputeState(state []parkedNodeState) {
+	for index, n := range s.nodes {
+		if n.Node().isNecessary() {
+			continue
+		}
+		n.Node().changedAt = state[index].changedAt
+		n.Node().recomputedAt = state[index].recomputedAt
+	}
+}
+
+func (s *bindKeyedScope[K, A]) isTopScope() bool { return false }
+func (s *bindKeyedScope[K, A]) isScopeValid() bool {
+	return !s.disposed && s.keyed.bind.isScopeValid()
+}
+func (s *bindKeyedScope[K, A]) isScopeNecessary() bool {
+	return s.keyed.current == s && s.keyed.bind.isScopeNecessary()
+}
+func (s *bindKeyedScope[K, A]) isScopeBuilding() bool { return s.building }
+func (s *bindKeyedScope[K, A]) scopeGraph() *Graph    { return s.keyed.bind.scopeGraph() }
+func (s *bindKeyedScope[K, A]) scopeHeight() int      { return s.keyed.bind.scopeHeight() }
+
+func (s *bindKeyedScope[K, A]) addScopeNode(n INode) {
+	s.nodes = append(s.nodes, n)
+	if s.keyed.current == s {
+		s.keyed.bind.rhsNodes = s.nodes
+	}
+}
+
+func (s *bindKeyedScope[K, A]) Defer(fn func()) {
+	s.deferred = append(s.deferred, fn)
+}
+
+func (s *bindKeyedScope[K, A]) String() string {
+	return fmt.Sprintf("{%v:%v}", s.keyed.bind.main, s.key)
+}
@@ -0,0 +1,320 @@
+package incr
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/wcharczuk/go-incr/testutil"
+)
+
+func Test_BindKeyed(t *testing.T) {
+	ctx := testContext()
+	g := New()
+
+	var built, recomputed []string
+	v := Var(g, "a")
+	b := BindKeyed(g, v, func(bs Scope, key string) Incr[string] {
+		built = append(built, key)
+		return Map(bs, Return(bs, key), func(vv string) string {
+			recomputed = append(recomputed, vv)
+			return vv + "-value"
+		})
+	})
+	o := MustObserve(g, b)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "a-value", o.Value())
+
+	v.Set("b")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "b-value", o.Value())
+
+	v.Set("a")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "a-value", o.Value())
+	testutil.Equal(t, []string{"a", "b"}, built)
+	testutil.Equal(t, []string{"a", "b"}, recomputed)
+
+	v.Set("a")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "a-value", o.Value())
+	testutil.Equal(t, []string{"a", "b"}, built)
+}
+
+func Test_BindKeyed_revivedScopeRecomputesChanges(t *testing.T) {
+	ctx := testContext()
+	g := New()
+
+	key := Var(g, "a")
+	inputs := map[string]VarIncr[string]{}
+	b := BindKeyed(g, key, func(bs Scope, which string) Incr[string] {
+		inputs[which] = Var(bs, which+"-0")
+		return Map(bs, inputs[which], ident)
+	})
+	o := MustObserve(g, b)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "a-0", o.Value())
+
+	key.Set("b")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "b-0", o.Value())
+
+	inputs["a"].Set("a-1")
+	key.Set("a")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "a-1", o.Value())
+}
+
+func Test_BindKeyed_maxParked(t *testing.T) {
+	ctx := testContext()
+	g := New()
+
+	var built, disposed []string
+	v := Var(g, "a")
+	b := BindKeyed(g, v, func(bs Scope, key string) Incr[string] {
+		built = append(built, key)
+		bs.Defer(func() { disposed = append(disposed, key) })
+		return Return(bs, key)
+	}, OptBindKeyedMaxParked(1))
+	o := MustObserve(g, b)
+
+	for _, key := range []string{"a", "b", "c", "b", "a"} {
+		v.Set(key)
+		err := g.Stabilize(ctx)
+		testutil.NoError(t, err)
+		testutil.Equal(t, key, o.Value())
+	}
+	testutil.Equal(t, []string{"a", "b", "c", "a"}, built)
+	testutil.Equal(t, []string{"a", "c"}, disposed)
+}
+
+func Test_BindKeyed_parkedTTL(t *testing.T) {
+	ctx := testContext()
+	g := New()
+
+	var built, disposed []string
+	v := Var(g, "a")
+	b := BindKeyed(g, v, func(bs Scope, key string) Incr[string] {
+		built = append(built, key)
+		return Return(bs, key)
+	}, OptBindKeyedParkedTTL(time.Minute))
+	b.OnScopeDisposed(func(s Scope) { disposed = append(disposed, s.String()) })
+	o := MustObserve(g, b)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	v.Set("b")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Empty(t, disposed)
+
+	keyed := b.(*bindMainIncr[string, string]).bind.scopes.(*bindKeyed[string, string])
+	testutil.Equal(t, 1, len(keyed.parked))
+	keyed.parked[0].parkedAt = keyed.parked[0].parkedAt.Add(-2 * time.Minute)
+
+	v.Set("c")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 1, len(disposed))
+	testutil.Equal(t, []string{"b"}, keyedParkedKeys(keyed))
+
+	v.Set("a")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "a", o.Value())
+	testutil.Equal(t, []string{"a", "b", "c", "a"}, built)
+}
+
+func Test_BindKeyed_invalidatedDisposesAll(t *testing.T) {
+	ctx := testContext()
+	g := New()
+
+	var disposed []string
+	outer := Var(g, "x")
+	inner := Var(g, "a")
+	b := Bind(g, outer, func(bs Scope, outerKey string) Incr[string] {
+		return BindKeyed(bs, inner, func(ibs Scope, key string) Incr[string] {
+			ibs.Defer(func() { disposed = append(disposed, outerKey+key) })
+			return Return(ibs, outerKey+key)
+		})
+	})
+	o := MustObserve(g, b)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	inner.Set("b")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "xb", o.Value())
+	testutil.Empty(t, disposed)
+
+	outer.Set("y")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "yb", o.Value())
+	testutil.Equal(t, []string{"xb", "xa"}, disposed)
+}
+
+func Test_BindKeyedContext_error(t *testing.T) {
+	ctx := testContext()
+	g := New()
+
+	var deferred []string
+	v := Var(g, "a")
+	b := BindKeyedContext(g, v, func(_ context.Context, bs Scope, key string) (Incr[string], error) {
+		bs.Defer(func() { deferred = append(deferred, key) })
+		if key == "bad" {
+			return nil, fmt.Errorf("this is just a test")
+		}
+		return Return(bs, key), nil
+	})
+	o := MustObserve(g, b)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+
+	v.Set("bad")
+	err = g.Stabilize(ctx)
+	testutil.Error(t, err)
+	testutil.Equal(t, []string{"bad"}, deferred)
+
+	v.Set("a")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "a", o.Value())
+}
+
+func keyedParkedKeys[K comparable, A any](bk *bindKeyed[K, A]) (out []K) {
+	for _, scope := range bk.parked {
+		out = append(out, scope.key)
+	}
+	return
+}
+
+func Test_BindKeyed_changeParentError(t *testing.T) {
+	ctx := testContext()
+	g := New()
+
+	var deferred []string
+	var b BindIncr[string]
+	v := Var(g, "a")
+	b = BindKeyed(g, v, func(bs Scope, key string) Incr[string] {
+		bs.Defer(func() { deferred = append(deferred, key) })
+		if key == "a" {
+			return Return(bs, "foo")
+		}
+		return Map(bs, b, ident)
+	})
+	_ = MustObserve(g, b)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+
+	v.Set("b")
+	err = g.Stabilize(ctx)
+	testutil.Error(t, err)
+	testutil.Equal(t, []string{"b"}, deferred)
+
+	keyed := b.(*bindMainIncr[string, string]).bind.scopes.(*bindKeyed[string, string])
+	testutil.Equal(t, "a", keyed.current.key)
+	keyed.disposeAll()
+	testutil.Equal(t, []string{"b", "a"}, deferred)
+}
+
+func Test_BindKeyed_changeParentError_switchBack(t *testing.T) {
+	ctx := testContext()
+	g := New()
+
+	w := Var(g, "w0")
+	var b BindIncr[string]
+	v := Var(g, "a")
+	var failed Incr[string]
+	b = BindKeyed(g, v, func(bs Scope, key string) Incr[string] {
+		if key == "a" {
+			return Map(bs, w, ident)
+		}
+		failed = Map(bs, b, ident)
+		return failed
+	})
+	o := MustObserve(g, b)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "w0", o.Value())
+
+	v.Set("b")
+	err = g.Stabilize(ctx)
+	testutil.Error(t, err)
+	testutil.Equal(t, false, failed.Node().valid, "the nodes of a scope that failed to link should be invalidated")
+
+	v.Set("a")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+
+	w.Set("w1")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "w1", o.Value())
+}
+
+func Test_BindKeyed_changeParentError_revived(t *testing.T) {
+	ctx := testContext()
+	g := New()
+
+	w := Var(g, "w0")
+	mn := MapN(g, func(values ...string) string {
+		return fmt.Sprint(values)
+	})
+	var b BindIncr[string]
+	v := Var(g, "a")
+	var disposed []string
+	b = BindKeyed(g, v, func(bs Scope, key string) Incr[string] {
+		bs.Defer(func() { disposed = append(disposed, key) })
+		if key == "a" {
+			return Map(bs, w, ident)
+		}
+		return Map(bs, mn, ident)
+	})
+	o := MustObserve(g, b)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	v.Set("b")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	v.Set("a")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "w0", o.Value())
+
+	// reviving the parked scope for "b" now creates a cycle.
+	testutil.NoError(t, mn.AddInput(b))
+	v.Set("b")
+	err = g.Stabilize(ctx)
+	testutil.Error(t, err)
+
+	keyed := b.(*bindMainIncr[string, string]).bind.scopes.(*bindKeyed[string, string])
+	testutil.Equal(t, "a", keyed.current.key)
+	testutil.Equal(t, 1, len(keyed.parked))
+	testutil.Equal(t, "b", keyed.parked[0].key)
+	testutil.Equal(t, false, keyed.parked[0].disposed)
+	testutil.Empty(t, disposed)
+
+	v.Set("a")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	w.Set("w1")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "w1", o.Value())
+}
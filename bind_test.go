@@ -1003,8 +1003,8 @@ func Test_Bind_nestedScopeHasGraph(t *testing.T) {
 	testutil.Equal(t, false, ibv00.Node().createdIn.isTopScope())
 	testutil.Equal(t, false, ibv01.Node().createdIn.isTopScope())
 
-	ibv00CreatedInID := ibv00.Node().createdIn.(*bind[string, string]).main.n.id
-	ibv01CreatedInID := ibv01.Node().createdIn.(*bind[string, string]).main.n.id
+	ibv00CreatedInID := ibv00.Node().createdIn.(*bind[string, string]).main.n.ID()
+	ibv01CreatedInID := ibv01.Node().createdIn.(*bind[string, string]).main.n.ID()
 	testutil.NotEqual(t, ibv00CreatedInID, ibv01CreatedInID)
 }
 
@@ -1208,6 +1208,60 @@ func Test_Bind_cycle(t *testing.T) {
 	testutil.Equal(t, "foo", o.Value())
 }
 
+func Test_Bind_recursive_deep(t *testing.T) {
+	ctx := testContext()
+	g := New()
+
+	const months = 400
+	rate := Var(g, 1)
+	var runway func(Scope, int) Incr[int]
+	runway = func(scope Scope, month int) Incr[int] {
+		if month == months {
+			return Return(scope, 0)
+		}
+		return Bind(scope, rate, func(bs Scope, r int) Incr[int] {
+			return Map(bs, runway(bs, month+1), func(rest int) int {
+				return rest + r
+			})
+		})
+	}
+
+	root := runway(g, 0)
+	o := MustObserve(g, root)
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, months, o.Value())
+	testutil.Equal(t, true, root.Node().height > DefaultMaxHeight)
+
+	rate.Set(2)
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 2*months, o.Value())
+}
+
+func Test_Bind_recursive_maxHeight(t *testing.T) {
+	ctx := testContext()
+	g := New(OptGraphMaxHeight(64))
+
+	rate := Var(g, 1)
+	var runway func(Scope, int) Incr[int]
+	runway = func(scope Scope, month int) Incr[int] {
+		if month == 400 {
+			return Return(scope, 0)
+		}
+		return Bind(scope, rate, func(bs Scope, r int) Incr[int] {
+			return Map(bs, runway(bs, month+1), func(rest int) int {
+				return rest + r
+			})
+		})
+	}
+
+	_ = MustObserve(g, runway(g, 0))
+	err := g.Stabilize(ctx)
+	testutil.Error(t, err)
+	testutil.Matches(t, "cannot set node height above 63", err.Error())
+}
+
 func Test_bind_scope(t *testing.T) {
 	g := New()
 
@@ -1267,3 +1321,232 @@ func Test_bindLeftChange_RightScopeNodes(t *testing.T) {
 
 	testutil.Equal(t, 2, len(bindTyped.bind.lhsChange.RightScopeNodes()))
 }
+
+func Test_Bind_scopeLifecycle(t *testing.T) {
+	ctx := testContext()
+	g := New()
+
+	var events []string
+	v := Var(g, "a")
+	b := Bind(g, v, func(bs Scope, which string) Incr[string] {
+		events = append(events, "build "+which)
+		bs.Defer(func() { events = append(events, "defer-0 "+which) })
+		bs.Defer(func() { events = append(events, "defer-1 "+which) })
+		return Return(bs, which)
+	})
+	b.OnScopeCreated(func(_ Scope) { events = append(events, "created") })
+	b.OnScopeDisposed(func(_ Scope) { events = append(events, "disposed") })
+	o := MustObserve(g, b)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "a", o.Value())
+	testutil.Equal(t, []string{"build a", "created"}, events)
+
+	events = nil
+	v.Set("b")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "b", o.Value())
+	testutil.Equal(t, []string{"build b", "defer-1 a", "defer-0 a", "disposed", "created"}, events)
+}
+
+func Test_Bind_scopeLifecycle_nested(t *testing.T) {
+	ctx := testContext()
+	g := New()
+
+	var disposed []string
+	outer := Var(g, "a")
+	inner := Var(g, "x")
+	b := Bind(g, outer, func(bs Scope, which string) Incr[string] {
+		innerBind := Bind(bs, inner, func(ibs Scope, innerWhich string) Incr[string] {
+			ibs.Defer(func() { disposed = append(disposed, "inner "+which+innerWhich) })
+			return Return(ibs, which+innerWhich)
+		})
+		bs.Defer(func() { disposed = append(disposed, "outer "+which) })
+		return innerBind
+	})
+	o := MustObserve(g, b)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "ax", o.Value())
+	testutil.Empty(t, disposed)
+
+	outer.Set("b")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "bx", o.Value())
+	testutil.Equal(t, []string{"inner ax", "outer a"}, disposed)
+}
+
+func Test_Bind_scopeLifecycle_error(t *testing.T) {
+	ctx := testContext()
+	g := New()
+
+	var deferred []string
+	v := Var(g, "a")
+	b := BindContext(g, v, func(_ context.Context, bs Scope, which string) (Incr[string], error) {
+		bs.Defer(func() { deferred = append(deferred, which) })
+		if which == "bad" {
+			return nil, fmt.Errorf("this is just a test")
+		}
+		return Return(bs, which), nil
+	})
+	var disposed int
+	b.OnScopeDisposed(func(_ Scope) { disposed++ })
+	_ = MustObserve(g, b)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+
+	v.Set("bad")
+	err = g.Stabilize(ctx)
+	testutil.Error(t, err)
+	testutil.Equal(t, []string{"bad"}, deferred)
+	testutil.Equal(t, 0, disposed)
+}
+
+func Test_Graph_Defer(t *testing.T) {
+	g := New()
+	var called bool
+	g.Defer(func() { called = true })
+	testutil.Equal(t, false, called)
+}
+
+func Test_Bind_scopeLifecycle_changeParentError(t *testing.T) {
+	ctx := testContext()
+	g := New()
+
+	var deferred []string
+	var b BindIncr[string]
+	v := Var(g, "a")
+	b = Bind(g, v, func(bs Scope, which string) Incr[string] {
+		bs.Defer(func() { deferred = append(deferred, which) })
+		if which == "a" {
+			return Return(bs, "foo")
+		}
+		return Map(bs, b, ident)
+	})
+	var created int
+	b.OnScopeCreated(func(_ Scope) { created++ })
+	_ = MustObserve(g, b)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 1, created)
+
+	v.Set("b")
+	err = g.Stabilize(ctx)
+	testutil.Error(t, err)
+	testutil.Equal(t, []string{"b"}, deferred)
+	testutil.Equal(t, 1, created)
+
+	bindTyped := b.(*bindMainIncr[string, string])
+	testutil.Equal(t, 1, len(bindTyped.bind.deferred))
+	runDeferred(bindTyped.bind.deferred)
+	testutil.Equal(t, []string{"b", "a"}, deferred)
+}
+
+func Test_Bind_error_restoresScope(t *testing.T) {
+	ctx := testContext()
+	g := New()
+
+	var deferred []string
+	var failed Incr[string]
+	w := Var(g, "w0")
+	v := Var(g, "a")
+	b := BindContext(g, v, func(_ context.Context, bs Scope, which string) (Incr[string], error) {
+		bs.Defer(func() { deferred = append(deferred, which) })
+		if which == "bad" {
+			failed = Map(bs, w, ident)
+			return nil, fmt.Errorf("this is just a test")
+		}
+		return Map(bs, w, func(vv string) string { return which + "-" + vv }), nil
+	})
+	o := MustObserve(g, b)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "a-w0", o.Value())
+
+	bindTyped := b.(*bindMainIncr[string, string])
+	rhs := bindTyped.bind.rhs
+
+	v.Set("bad")
+	err = g.Stabilize(ctx)
+	testutil.Error(t, err)
+	testutil.Equal(t, []string{"bad"}, deferred)
+	testutil.Equal(t, false, failed.Node().valid, "the nodes of a scope that failed to build should be invalidated")
+	testutil.Equal(t, true, bindTyped.bind.rhs == rhs, "the previous scope should be kept")
+	testutil.Equal(t, []INode{rhs}, bindTyped.bind.rhsNodes)
+	testutil.Equal(t, []INode{bindTyped.bind.lhsChange, rhs}, bindTyped.Parents())
+	testutil.Equal(t, []INode{b}, rhs.Node().children)
+
+	w.Set("w1")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "a-w1", o.Value())
+
+	v.Set("c")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "c-w1", o.Value())
+	testutil.Equal(t, []string{"bad", "a"}, deferred)
+	testutil.Equal(t, false, rhs.Node().valid, "the previous scope should be disposed on the next change")
+	testutil.Equal(t, false, g.Has(rhs))
+	testutil.Equal(t, 1, len(w.Node().children))
+}
+
+func Test_Bind_changeParentError_restoresScope(t *testing.T) {
+	ctx := testContext()
+	g := New()
+
+	var deferred []string
+	var failed Incr[string]
+	var b BindIncr[string]
+	w := Var(g, "w0")
+	v := Var(g, "a")
+	b = Bind(g, v, func(bs Scope, which string) Incr[string] {
+		bs.Defer(func() { deferred = append(deferred, which) })
+		if which == "bad" {
+			failed = Map(bs, b, ident)
+			return failed
+		}
+		return Map(bs, w, func(vv string) string { return which + "-" + vv })
+	})
+	o := MustObserve(g, b)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "a-w0", o.Value())
+
+	bindTyped := b.(*bindMainIncr[string, string])
+	rhs := bindTyped.bind.rhs
+
+	v.Set("bad")
+	err = g.Stabilize(ctx)
+	testutil.Error(t, err)
+	testutil.Equal(t, []string{"bad"}, deferred)
+	testutil.Equal(t, false, failed.Node().valid, "the nodes of a scope that failed to link should be invalidated")
+	testutil.Equal(t, true, bindTyped.bind.rhs == rhs, "the previous scope should be kept")
+	testutil.Equal(t, []INode{rhs}, bindTyped.bind.rhsNodes)
+	testutil.Equal(t, []INode{bindTyped.bind.lhsChange, rhs}, bindTyped.Parents())
+	testutil.Equal(t, []INode{b}, rhs.Node().children)
+	testutil.Equal(t, false, rhs.Node().forceNecessary)
+	testutil.Equal(t, 0, len(failed.Node().children), "the failed scope should not stay linked to the bind")
+
+	w.Set("w1")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "a-w1", o.Value())
+
+	v.Set("c")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "c-w1", o.Value())
+	testutil.Equal(t, []string{"bad", "a"}, deferred)
+	testutil.Equal(t, false, rhs.Node().valid, "the previous scope should be disposed on the next change")
+	testutil.Equal(t, false, g.Has(rhs))
+	testutil.Equal(t, 1, len(w.Node().children))
+}
@@ -32,6 +32,14 @@ func CutoffContext[A any](bs Scope, i Incr[A], fn CutoffContextFunc[A]) Incr[A]
 // CutoffFunc is a function that implements cutoff checking.
 type CutoffFunc[A any] func(A, A) bool
 
+// Equals returns if two comparable values are equal.
+//
+// It can be passed as the equality function to [VarWithEqual] and [MapWithEqual], and
+// as the [CutoffFunc] to [Cutoff] to cut off recomputation if a value doesn't change.
+func Equals[A comparable](a, b A) bool {
+	return a == b
+}
+
 // CutoffContextFunc is a function that implements cutoff checking
 // and takes a context.
 type CutoffContextFunc[A any] func(context.Context, A, A) (bool, error)
@@ -58,6 +66,7 @@ func (c *cutoffIncr[A]) Parents() []INode {
 }
 
 func (c *cutoffIncr[A]) Value() A {
+	c.n.checkRead(c)
 	return c.value
 }
 
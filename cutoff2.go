@@ -55,6 +55,7 @@ func (c *cutoff2Incr[A, B]) Parents() []INode {
 }
 
 func (c *cutoff2Incr[A, B]) Value() B {
+	c.n.checkRead(c)
 	return c.value
 }
 
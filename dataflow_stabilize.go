@@ -0,0 +1,263 @@
+package incr
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// DataflowStabilize stabilizes a graph in parallel without synchronizing on heights.
+//
+// Where [Graph.ParallelStabilize] processes a height "block" at a time, waiting for every
+// node in the block to finish before starting the next block, [Graph.DataflowStabilize] tracks the
+// number of parents each stale node is waiting on and dispatches the node to a worker as soon
+// as its parents have finished. As a result a long running node only delays the nodes that
+// actually depend on it.
+//
+// The nodes are recomputed on the graph worker pool, the size of which is set by [OptGraphParallelism],
+// or on the node's executor if one is set with [Node.SetExecutor].
+//
+// Because [Bind] nodes change the structure of the graph as they're stabilized, bind
+// left-hand-side changes are processed exclusively of other nodes, and the nodes that
+// depend on the changed structure are processed in a subsequent pass.
+//
+// The resulting node values are the same as the values produced by [Graph.Stabilize].
+func (graph *Graph) DataflowStabilize(ctx context.Context) (err error) {
+	if err = graph.ensureNotStabilizing(ctx); err != nil {
+		return
+	}
+	ctx = graph.stabilizeStart(ctx)
+	defer func() {
+		graph.stabilizeEnd(ctx, err)
+	}()
+	err = graph.dataflowStabilize(ctx)
+	return
+}
+
+func (graph *Graph) dataflowStabilize(ctx context.Context) (err error) {
+	graph.checkSentinels(ctx)
+	var immediateRecompute []INode
+	for graph.recomputeHeap.len() > 0 {
+		df := newDataflow(graph, graph.recomputeHeap.removeAll())
+		err = df.run(ctx)
+		immediateRecompute = append(immediateRecompute, df.always...)
+		if err != nil {
+			break
+		}
+	}
+	if len(immediateRecompute) > 0 {
+		for _, n := range immediateRecompute {
+			graph.recomputeHeap.addIfNotPresent(n)
+		}
+	}
+	return
+}
+
+// dataflowNode is a node in the cone of nodes a dataflow pass may recompute.
+type dataflowNode struct {
+	node INode
+	// initial is true if the node was in the recompute heap.
+	initial bool
+	// pending is the number of parents in the cone that have not finished.
+	pending int32
+	// parentChanged is set if a parent in the cone changed.
+	parentChanged atomic.Bool
+	// deferred is set if the node should be recomputed in a later pass.
+	deferred atomic.Bool
+	children []*dataflowNode
+}
+
+// dataflow is a single pass over the cone of nodes reachable from the
+// nodes in the recompute heap at the start of the pass.
+type dataflow struct {
+	graph *Graph
+	nodes []*dataflowNode
+
+	// structureMu is held exclusively while bind left-hand-side
+	// changes are stabilized, and shared for all other nodes.
+	structureMu sync.RWMutex
+
+	ready chan *dataflowNode
+	wg    sync.WaitGroup
+
+	errOnce sync.Once
+	err     error
+	failed  atomic.Bool
+
+	alwaysMu sync.Mutex
+	always   []INode
+}
+
+func newDataflow(graph *Graph, initial []INode) *dataflow {
+	lookup := make(map[*Node]*dataflowNode, len(initial))
+	df := &dataflow{
+		graph: graph,
+		nodes: make([]*dataflowNode, 0, len(initial)),
+	}
+	visit := func(n INode, isInitial bool) *dataflowNode {
+		if dn, ok := lookup[n.Node()]; ok {
+			dn.initial = dn.initial || isInitial
+			return dn
+		}
+		dn := &dataflowNode{node: n, initial: isInitial}
+		lookup[n.Node()] = dn
+		df.nodes = append(df.nodes, dn)
+		return dn
+	}
+	for _, n := range initial {
+		_ = visit(n, true)
+	}
+
+	// visit the cone breadth first; edges are only followed to nodes
+	// with greater heights which guarantees the cone is acyclic.
+	linked := make(map[[2]*Node]struct{})
+	link := func(parent *dataflowNode, child INode) {
+		cn := child.Node()
+		if !cn.isNecessary() || cn.height <= parent.node.Node().height {
+			return
+		}
+		key := [2]*Node{parent.node.Node(), cn}
+		if _, ok := linked[key]; ok {
+			return
+		}
+		linked[key] = struct{}{}
+		dc := visit(child, false)
+		dc.pending++
+		parent.children = append(parent.children, dc)
+	}
+	for x := 0; x < len(df.nodes); x++ {
+		dn := df.nodes[x]
+		for _, c := range dn.node.Node().children {
+			link(dn, c)
+		}
+		if typed, ok := dn.node.(IBindChange); ok {
+			for _, rn := range typed.RightScopeNodes() {
+				link(dn, rn)
+			}
+		}
+	}
+	return df
+}
+
+func (df *dataflow) run(ctx context.Context) error {
+	if len(df.nodes) == 0 {
+		return nil
+	}
+	df.ready = make(chan *dataflowNode, len(df.nodes))
+	df.wg.Add(len(df.nodes))
+	for _, dn := range df.nodes {
+		if dn.pending == 0 {
+			df.ready <- dn
+		}
+	}
+
+	parallelism := min(df.graph.workerPool.size, len(df.nodes))
+	var workers sync.WaitGroup
+	workers.Add(parallelism)
+	for x := 0; x < parallelism; x++ {
+		df.graph.workerPool.submit(func() {
+			defer workers.Done()
+			for dn := range df.ready {
+				df.process(ctx, dn)
+			}
+		})
+	}
+	df.wg.Wait()
+	close(df.ready)
+	workers.Wait()
+
+	df.requeueDeferred()
+	return df.err
+}
+
+func (df *dataflow) process(ctx context.Context, dn *dataflowNode) {
+	defer df.wg.Done()
+
+	_, isBindChange := dn.node.(IBindChange)
+	if isBindChange {
+		df.structureMu.Lock()
+	} else {
+		df.structureMu.RLock()
+	}
+	changed := df.processLocked(ctx, dn, isBindChange)
+	if isBindChange {
+		df.structureMu.Unlock()
+	} else {
+		df.structureMu.RUnlock()
+	}
+
+	for _, c := range dn.children {
+		if changed {
+			c.parentChanged.Store(true)
+		}
+		if dn.deferred.Load() {
+			c.deferred.Store(true)
+		}
+		if atomic.AddInt32(&c.pending, -1) == 0 {
+			df.ready <- c
+		}
+	}
+}
+
+func (df *dataflow) processLocked(ctx context.Context, dn *dataflowNode, isBindChange bool) (changed bool) {
+	nn := dn.node.Node()
+	if !nn.valid || nn.height == HeightUnset {
+		return
+	}
+	if !dn.initial && !(dn.parentChanged.Load() && nn.isNecessary() && nn.isStale()) {
+		return
+	}
+	if dn.deferred.Load() || df.failed.Load() || df.isInRecomputeHeap(dn.node) {
+		dn.deferred.Store(true)
+		return
+	}
+
+	var err error
+	executeNode(dn.node, func() {
+		changed, err = df.graph.recomputeNode(ctx, dn.node, true /*parallel*/)
+	})
+	if nn.always {
+		df.alwaysMu.Lock()
+		df.always = append(df.always, dn.node)
+		df.alwaysMu.Unlock()
+	}
+	if err != nil {
+		df.errOnce.Do(func() {
+			df.err = err
+			df.failed.Store(true)
+		})
+		return
+	}
+
+	// the nodes past a changed bind may depend on nodes that were
+	// linked by the change, so they're recomputed in the next pass.
+	if changed && isBindChange {
+		for _, c := range dn.children {
+			c.deferred.Store(true)
+		}
+	}
+	return
+}
+
+func (df *dataflow) isInRecomputeHeap(n INode) bool {
+	df.graph.recomputeHeap.mu.Lock()
+	defer df.graph.recomputeHeap.mu.Unlock()
+	return n.Node().heightInRecomputeHeap != HeightUnset
+}
+
+// requeueDeferred adds the nodes that were deferred to
+// the recompute heap if they still need to be recomputed.
+func (df *dataflow) requeueDeferred() {
+	for _, dn := range df.nodes {
+		if !dn.deferred.Load() {
+			continue
+		}
+		nn := dn.node.Node()
+		if !nn.valid || nn.height == HeightUnset {
+			continue
+		}
+		if dn.initial || (nn.isNecessary() && nn.isStale()) {
+			df.graph.recomputeHeap.addIfNotPresent(dn.node)
+		}
+	}
+}
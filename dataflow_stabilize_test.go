@@ -0,0 +1,244 @@
+package incr
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/wcharczuk/go-incr/testutil"
+)
+
+func Test_DataflowStabilize(t *testing.T) {
+	ctx := testContext()
+	g := New()
+
+	v0 := Var(g, "foo")
+	v1 := Var(g, "bar")
+	m0 := Map2(g, v0, v1, func(a, b string) string {
+		return a + " " + b
+	})
+
+	_ = MustObserve(g, m0)
+
+	err := g.DataflowStabilize(ctx)
+	testutil.Nil(t, err)
+	testutil.Equal(t, 1, m0.Node().changedAt)
+	testutil.Equal(t, 1, m0.Node().recomputedAt)
+	testutil.Equal(t, "foo bar", m0.Value())
+
+	v0.Set("not foo")
+	err = g.DataflowStabilize(ctx)
+	testutil.Nil(t, err)
+
+	testutil.Equal(t, 2, v0.Node().changedAt)
+	testutil.Equal(t, 0, v1.Node().changedAt)
+	testutil.Equal(t, 2, m0.Node().changedAt)
+	testutil.Equal(t, 0, v1.Node().recomputedAt)
+	testutil.Equal(t, 2, m0.Node().recomputedAt)
+	testutil.Equal(t, "not foo bar", m0.Value())
+	testutil.Equal(t, 0, g.recomputeHeap.len())
+}
+
+func Test_DataflowStabilize_alreadyStabilizing(t *testing.T) {
+	ctx := testContext()
+
+	graph := New()
+	graph.status = StatusStabilizing
+
+	err := graph.DataflowStabilize(ctx)
+	testutil.NotNil(t, err)
+}
+
+func Test_DataflowStabilize_error(t *testing.T) {
+	ctx := testContext()
+	g := New()
+
+	v0 := Var(g, "foo")
+	m0 := MapContext(g, v0, func(ctx context.Context, a string) (string, error) {
+		return "", fmt.Errorf("this is only a test")
+	})
+	m1 := Map(g, m0, ident)
+
+	_ = MustObserve(g, m1)
+
+	err := g.DataflowStabilize(ctx)
+	testutil.Error(t, err)
+	testutil.Equal(t, "this is only a test", err.Error())
+	testutil.Equal(t, 0, m1.Node().recomputedAt)
+}
+
+func Test_DataflowStabilize_Always(t *testing.T) {
+	ctx := testContext()
+	g := New()
+
+	v := Var(g, "foo")
+	m0 := Map(g, v, ident)
+	a := Always(g, m0)
+	m1 := Map(g, a, ident)
+
+	var updates int
+	m1.Node().OnUpdate(func(_ context.Context) {
+		updates++
+	})
+
+	o := MustObserve(g, m1)
+
+	_ = g.DataflowStabilize(ctx)
+	testutil.Equal(t, "foo", o.Value())
+	testutil.Equal(t, 1, updates)
+
+	_ = g.DataflowStabilize(ctx)
+	testutil.Equal(t, "foo", o.Value())
+	testutil.Equal(t, 2, updates)
+
+	v.Set("bar")
+
+	_ = g.DataflowStabilize(ctx)
+	testutil.Equal(t, "bar", o.Value())
+	testutil.Equal(t, 3, updates)
+}
+
+func Test_DataflowStabilize_notHeightSynchronous(t *testing.T) {
+	ctx := testContext()
+	g := New(OptGraphParallelism(4))
+
+	// the slow node waits on a node at a greater height that is
+	// independent of it, which would deadlock if nodes were processed
+	// a height block at a time.
+	fastDone := make(chan struct{})
+	v0 := Var(g, "slow")
+	slow := MapContext(g, v0, func(_ context.Context, v string) (string, error) {
+		select {
+		case <-fastDone:
+			return v, nil
+		case <-time.After(5 * time.Second):
+			return "", fmt.Errorf("timed out waiting for fast node")
+		}
+	})
+
+	v1 := Var(g, "fast")
+	f0 := Map(g, v1, ident)
+	f1 := Map(g, f0, ident)
+	f2 := Map(g, f1, func(v string) string {
+		close(fastDone)
+		return v
+	})
+	o := MustObserve(g, Map2(g, slow, f2, concat))
+	testutil.Equal(t, 1, slow.Node().height)
+	testutil.Equal(t, 3, f2.Node().height)
+
+	err := g.DataflowStabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "slowfast", o.Value())
+}
+
+func Test_DataflowStabilize_Bind_jsCombination(t *testing.T) {
+	ctx := testContext()
+	g := New(OptGraphParallelism(4))
+
+	v1 := Var(g, 1)
+	v2 := Var(g, 2)
+	v3 := Var(g, 3)
+	v4 := Var(g, 4)
+
+	o := MustObserve(g, Bind4(g, v1, v2, v3, v4, func(bs Scope, x1, x2, x3, x4 int) Incr[int] {
+		return Bind3(bs, v2, v3, v3, func(bs Scope, y2, y3, y4 int) Incr[int] {
+			return Bind2(bs, v4, v4, func(bs Scope, z3, z4 int) Incr[int] {
+				return Bind(bs, v4, func(bs Scope, w4 int) Incr[int] {
+					return Return(bs, x1+x2+x3+x4+y2+y3+y4+z3+z4+w4)
+				})
+			})
+		})
+	}))
+
+	err := g.DataflowStabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, v1.Value()+(2*v2.Value())+(3*v3.Value())+(4*v4.Value()), o.Value())
+
+	v1.Set(9)
+	v2.Set(10)
+	v3.Set(11)
+	v4.Set(12)
+
+	err = g.DataflowStabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, v1.Value()+(2*v2.Value())+(3*v3.Value())+(4*v4.Value()), o.Value())
+}
+
+func Test_DataflowStabilize_matchesStabilize(t *testing.T) {
+	ctx := testContext()
+	for seed := int64(0); seed < 16; seed++ {
+		t.Run(fmt.Sprint(seed), func(t *testing.T) {
+			sequential := New()
+			sequentialVars, sequentialObservers := makeDataflowTestGraph(sequential, rand.New(rand.NewSource(seed)))
+			dataflow := New(OptGraphParallelism(4))
+			dataflowVars, dataflowObservers := makeDataflowTestGraph(dataflow, rand.New(rand.NewSource(seed)))
+
+			sets := rand.New(rand.NewSource(seed))
+			for round := 0; round < 8; round++ {
+				err := sequential.Stabilize(ctx)
+				testutil.NoError(t, err)
+				err = dataflow.DataflowStabilize(ctx)
+				testutil.NoError(t, err)
+				for x := range sequentialObservers {
+					testutil.Equal(t, sequentialObservers[x].Value(), dataflowObservers[x].Value(), fmt.Sprintf("round %d observer %d", round, x))
+				}
+				testutil.Equal(t, 0, dataflow.recomputeHeap.len())
+
+				for x := 0; x < 1+sets.Intn(len(sequentialVars)); x++ {
+					index, value := sets.Intn(len(sequentialVars)), sets.Intn(100)
+					sequentialVars[index].Set(value)
+					dataflowVars[index].Set(value)
+				}
+			}
+		})
+	}
+}
+
+// makeDataflowTestGraph creates a random graph of maps and binds over a set
+// of vars, such that the same random source creates the same graph.
+func makeDataflowTestGraph(g *Graph, r *rand.Rand) (vars []VarIncr[int], observers []ObserveIncr[int]) {
+	var nodes []Incr[int]
+	for x := 0; x < 8; x++ {
+		v := Var(g, r.Intn(100))
+		vars = append(vars, v)
+		nodes = append(nodes, v)
+	}
+	pick := func() Incr[int] {
+		return nodes[r.Intn(len(nodes))]
+	}
+	for x := 0; x < 64; x++ {
+		switch r.Intn(4) {
+		case 0:
+			nodes = append(nodes, Map(g, pick(), func(a int) int {
+				return a + 1
+			}))
+		case 1:
+			nodes = append(nodes, Map2(g, pick(), pick(), func(a, b int) int {
+				return a + b
+			}))
+		case 2:
+			nodes = append(nodes, Cutoff(g, pick(), func(previous, current int) bool {
+				return previous%10 == current%10
+			}))
+		default:
+			left, right, other := pick(), pick(), pick()
+			nodes = append(nodes, Bind(g, left, func(bs Scope, v int) Incr[int] {
+				if v%2 == 0 {
+					return Map(bs, right, func(a int) int {
+						return a * 2
+					})
+				}
+				return Map2(bs, right, other, func(a, b int) int {
+					return a - b
+				})
+			}))
+		}
+	}
+	for x := len(nodes) - 8; x < len(nodes); x++ {
+		observers = append(observers, MustObserve(g, nodes[x]))
+	}
+	return
+}
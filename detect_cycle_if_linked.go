@@ -18,23 +18,23 @@ func DetectCycleIfLinked(child, parent INode) error {
 		return nil
 	}
 	getParentsWithPossibleParent := func(n INode) []INode {
-		if n.Node().ID() == child.Node().ID() {
+		if n.Node() == child.Node() {
 			return append(getParents(n), parent)
 		}
 		return getParents(n)
 	}
-	if detectCycleFast(child.Node().ID(), parent /*startAt*/, getParentsWithPossibleParent) {
+	if detectCycleFast(child.Node(), parent /*startAt*/, getParentsWithPossibleParent) {
 		return fmt.Errorf("adding %v as child of %v would cause a cycle", child, parent)
 	}
 	return nil
 }
 
-func detectCycleFast(childID Identifier, startAt INode, getParents func(INode) []INode) bool {
-	if startAt.Node().ID() == childID {
+func detectCycleFast(child *Node, startAt INode, getParents func(INode) []INode) bool {
+	if startAt.Node() == child {
 		return true
 	}
 	for _, p := range getParents(startAt) {
-		if detectCycleFast(childID, p, getParents) {
+		if detectCycleFast(child, p, getParents) {
 			return true
 		}
 	}
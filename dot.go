@@ -34,25 +34,31 @@ func Dot(wr io.Writer, g *Graph) (err error) {
 	}
 
 	writef(0, "digraph {")
-	nodes := make([]INode, 0, len(g.nodes)+len(g.observers))
+	nodes := make([]INode, 0, g.numNodes)
 	for _, n := range g.nodes {
-		nodes = append(nodes, n)
+		if n != nil {
+			nodes = append(nodes, n)
+		}
 	}
 	for _, o := range g.observers {
-		nodes = append(nodes, o)
+		if o != nil {
+			nodes = append(nodes, o)
+		}
 	}
 	for _, o := range g.sentinels {
-		nodes = append(nodes, o)
+		if o != nil {
+			nodes = append(nodes, o)
+		}
 	}
 
 	slices.SortStableFunc(nodes, nodeSorter)
 
-	nodeLabels := make(map[Identifier]string)
+	nodeLabels := make(map[*Node]string)
 	for index, n := range nodes {
 		nodeLabel := fmt.Sprintf("n%d", index+1)
 
 		var nodeInternalLabelParts []string
-		nodeInternalLabelParts = append(nodeInternalLabelParts, fmt.Sprintf("%s:%s", n.Node().kind, n.Node().id.Short()))
+		nodeInternalLabelParts = append(nodeInternalLabelParts, fmt.Sprintf("%s:%s", n.Node().kind, n.Node().ID().Short()))
 		if n.Node().label != "" {
 			nodeInternalLabelParts = append(nodeInternalLabelParts, fmt.Sprintf("label: %s", n.Node().label))
 		}
@@ -71,18 +77,18 @@ func Dot(wr io.Writer, g *Graph) (err error) {
 			color = ` fillcolor = "pink" style="filled" fontcolor="black"`
 		}
 		writef(1, "node [%s%s]; %s", label, color, nodeLabel)
-		nodeLabels[n.Node().id] = nodeLabel
+		nodeLabels[n.Node()] = nodeLabel
 	}
 	for _, n := range nodes {
-		nodeLabel := nodeLabels[n.Node().id]
+		nodeLabel := nodeLabels[n.Node()]
 		for _, p := range n.Node().children {
-			childLabel, ok := nodeLabels[p.Node().id]
+			childLabel, ok := nodeLabels[p.Node()]
 			if ok {
 				writef(1, "%s -> %s;", nodeLabel, childLabel)
 			}
 		}
 		for _, o := range n.Node().observers {
-			childLabel, ok := nodeLabels[o.Node().id]
+			childLabel, ok := nodeLabels[o.Node()]
 			if ok {
 				writef(1, "%s -> %s;", nodeLabel, childLabel)
 			}
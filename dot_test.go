@@ -28,10 +28,10 @@ func Test_Dot(t *testing.T) {
 
 	testutil.NotEqual(t, "", buffer.String())
 
-	testutil.Equal(t, true, strings.Contains(buffer.String(), o.Node().id.Short()))
-	testutil.Equal(t, true, strings.Contains(buffer.String(), s.Node().id.Short()))
-	testutil.Equal(t, true, strings.Contains(buffer.String(), m2.Node().id.Short()))
-	testutil.Equal(t, true, strings.Contains(buffer.String(), m3.Node().id.Short()))
-	testutil.Equal(t, true, strings.Contains(buffer.String(), v0.Node().id.Short()))
-	testutil.Equal(t, true, strings.Contains(buffer.String(), v1.Node().id.Short()))
+	testutil.Equal(t, true, strings.Contains(buffer.String(), o.Node().ID().Short()))
+	testutil.Equal(t, true, strings.Contains(buffer.String(), s.Node().ID().Short()))
+	testutil.Equal(t, true, strings.Contains(buffer.String(), m2.Node().ID().Short()))
+	testutil.Equal(t, true, strings.Contains(buffer.String(), m3.Node().ID().Short()))
+	testutil.Equal(t, true, strings.Contains(buffer.String(), v0.Node().ID().Short()))
+	testutil.Equal(t, true, strings.Contains(buffer.String(), v1.Node().ID().Short()))
 }
@@ -5,4 +5,7 @@ import "errors"
 var (
 	// ErrAlreadyStabilizing is returned if you're already stabilizing a graph.
 	ErrAlreadyStabilizing = errors.New("stabilize; already stabilizing, cannot continue")
+	// ErrUnsupportedVar is returned by [SetMany] if a var assignment is for a var
+	// that was not created with [Var] or [VarWithEqual].
+	ErrUnsupportedVar = errors.New("set many; unsupported var, vars must be created with Var or VarWithEqual")
 )
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/wcharczuk/go-incr"
+	"github.com/wcharczuk/go-incr/incrutil"
 )
 
 func main() {
@@ -15,9 +16,9 @@ func main() {
 
 	values := spigot(ctx)
 
-	g := incr.New()
+	g := incr.New(incr.OptGraphConcurrent(true))
 
-	currentValue := incr.Var(g, 0.0)
+	currentValue := incrutil.VarFromChan(ctx, g, values, incrutil.ChanOptions{})
 	lastValues := lastN(g, 10, currentValue)
 	max := maxOf(g, currentValue, 0.01)
 	min := minOf(g, currentValue, 0.01)
@@ -53,11 +54,14 @@ func main() {
 		}
 	})
 
-	for {
-		value := <-values
-		currentValue.Set(value)
-		_ = g.Stabilize(ctx)
-	}
+	as := incrutil.AutoStabilizer(ctx, g, incrutil.AutoStabilizerOptions{})
+	defer as.Stop()
+	go func() {
+		for err := range as.Errors() {
+			fmt.Printf("stabilization error: %v\n", err)
+		}
+	}()
+	<-ctx.Done()
 }
 
 func countUpdates[T any](scope incr.Scope, input incr.Incr[T]) incr.Incr[uint64] {
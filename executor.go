@@ -0,0 +1,121 @@
+package incr
+
+import (
+	"runtime"
+	"sync"
+)
+
+// CostHint tells parallel stabilization how expensive a node is to recompute.
+//
+// Set it on a node with [Node.SetCostHint].
+type CostHint int
+
+// CostHint values.
+const (
+	// CostHintDefault lets parallel stabilization estimate the cost of the node
+	// from the nodes recomputed previously, and is the default.
+	CostHintDefault CostHint = iota
+	// CostHintCheap indicates the node is cheap to recompute, and
+	// will always be recomputed on the stabilizing goroutine.
+	CostHintCheap
+	// CostHintExpensive indicates the node is expensive to recompute, e.g. if
+	// it makes a network call, and will always be recomputed concurrently with
+	// other nodes.
+	CostHintExpensive
+)
+
+// Executor runs node recomputations for parallel stabilization.
+//
+// Set it on a node with [Node.SetExecutor].
+type Executor interface {
+	// Execute calls a given function, returning after the function returns.
+	//
+	// Execute may be called from multiple goroutines at once.
+	Execute(func())
+}
+
+// ExecutorFunc is a function that implements [Executor].
+type ExecutorFunc func(func())
+
+// Execute implements [Executor].
+func (ef ExecutorFunc) Execute(fn func()) { ef(fn) }
+
+// NewDedicatedExecutor returns a new executor with a fixed number of
+// goroutines that are each locked to their own operating system thread.
+//
+// A dedicated executor with a single goroutine can be used for nodes
+// that call code that is not safe to call from multiple threads, or that
+// must be called from the same thread, e.g. some C libraries.
+//
+// The executor should be closed with [DedicatedExecutor.Close] once
+// the nodes that use it are no longer stabilized.
+func NewDedicatedExecutor(workers int) *DedicatedExecutor {
+	if workers < 1 {
+		workers = 1
+	}
+	de := &DedicatedExecutor{
+		work: make(chan dedicatedExecutorWork),
+		done: make(chan struct{}),
+	}
+	de.wg.Add(workers)
+	for x := 0; x < workers; x++ {
+		go de.worker()
+	}
+	return de
+}
+
+var _ Executor = (*DedicatedExecutor)(nil)
+
+// DedicatedExecutor is an [Executor] that runs functions on a fixed
+// set of goroutines, each locked to its own operating system thread.
+type DedicatedExecutor struct {
+	work      chan dedicatedExecutorWork
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+type dedicatedExecutorWork struct {
+	fn       func()
+	finished chan struct{}
+}
+
+// Execute implements [Executor].
+//
+// Execute panics if the executor has been closed.
+func (de *DedicatedExecutor) Execute(fn func()) {
+	work := dedicatedExecutorWork{
+		fn:       fn,
+		finished: make(chan struct{}),
+	}
+	select {
+	case <-de.done:
+		panic("incr; dedicated executor is closed")
+	case de.work <- work:
+	}
+	<-work.finished
+}
+
+// Close stops the executor goroutines, waiting for
+// any functions that are executing to return.
+func (de *DedicatedExecutor) Close() {
+	de.closeOnce.Do(func() {
+		close(de.done)
+	})
+	de.wg.Wait()
+}
+
+func (de *DedicatedExecutor) worker() {
+	defer de.wg.Done()
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	for {
+		select {
+		case <-de.done:
+			return
+		case work := <-de.work:
+			work.fn()
+			close(work.finished)
+		}
+	}
+}
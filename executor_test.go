@@ -0,0 +1,54 @@
+package incr
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/wcharczuk/go-incr/testutil"
+)
+
+func Test_DedicatedExecutor(t *testing.T) {
+	de := NewDedicatedExecutor(1)
+	defer de.Close()
+
+	var inFlight, maxInFlight int32
+	wg := new(sync.WaitGroup)
+	for x := 0; x < 8; x++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			de.Execute(func() {
+				current := atomic.AddInt32(&inFlight, 1)
+				if current > atomic.LoadInt32(&maxInFlight) {
+					atomic.StoreInt32(&maxInFlight, current)
+				}
+				atomic.AddInt32(&inFlight, -1)
+			})
+		}()
+	}
+	wg.Wait()
+	testutil.Equal(t, 1, maxInFlight)
+}
+
+func Test_DedicatedExecutor_Execute_returnsAfterCall(t *testing.T) {
+	de := NewDedicatedExecutor(2)
+	defer de.Close()
+
+	var called bool
+	de.Execute(func() {
+		called = true
+	})
+	testutil.Equal(t, true, called)
+}
+
+func Test_DedicatedExecutor_Close(t *testing.T) {
+	de := NewDedicatedExecutor(0)
+	de.Close()
+	de.Close()
+
+	defer func() {
+		testutil.NotNil(t, recover())
+	}()
+	de.Execute(func() {})
+}
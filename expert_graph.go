@@ -71,8 +71,15 @@ func (eg *expertGraph) NumNodes() uint64 {
 	return eg.graph.numNodes
 }
 
-func (eg *expertGraph) NumObservers() uint64 {
-	return uint64(len(eg.graph.observers))
+func (eg *expertGraph) NumObservers() (count uint64) {
+	eg.graph.observersMu.Lock()
+	defer eg.graph.observersMu.Unlock()
+	for _, o := range eg.graph.observers {
+		if o != nil {
+			count++
+		}
+	}
+	return
 }
 
 func (eg *expertGraph) NumNodesRecomputed() uint64 {
@@ -110,7 +117,7 @@ func (eg *expertGraph) RecomputeHeapIDs() []Identifier {
 	for _, height := range eg.graph.recomputeHeap.heights {
 		if height != nil {
 			for key := range height.items {
-				output = append(output, key)
+				output = append(output, key.ID())
 			}
 		}
 	}
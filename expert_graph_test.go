@@ -60,6 +60,6 @@ func Test_ExpertGraph_RecomputeHeapIDs(t *testing.T) {
 
 	recomputeHeapIDs := eg.RecomputeHeapIDs()
 	testutil.Equal(t, 2, len(recomputeHeapIDs))
-	testutil.Any(t, recomputeHeapIDs, func(id Identifier) bool { return id == n1.n.id })
-	testutil.Any(t, recomputeHeapIDs, func(id Identifier) bool { return id == n2.n.id })
+	testutil.Any(t, recomputeHeapIDs, func(id Identifier) bool { return id == n1.n.ID() })
+	testutil.Any(t, recomputeHeapIDs, func(id Identifier) bool { return id == n2.n.ID() })
 }
@@ -90,7 +90,7 @@ func (en *expertNode) CreatedIn() Scope         { return en.node.createdIn }
 func (en *expertNode) SetCreatedIn(scope Scope) { en.node.createdIn = scope }
 
 func (en *expertNode) SetID(id Identifier) {
-	en.node.id = id
+	en.node.id.Store(&id)
 }
 
 func (en *expertNode) Valid() bool {
@@ -188,15 +188,21 @@ func (en *expertNode) Observers() []IObserver {
 }
 
 func (en *expertNode) RemoveChild(id Identifier) {
-	en.node.removeChild(id)
+	if index := indexOfID(en.node.children, id); index >= 0 {
+		en.node.removeChild(en.node.children[index].Node())
+	}
 }
 
 func (en *expertNode) RemoveParent(id Identifier) {
-	en.node.removeParent(id)
+	if index := indexOfID(en.node.parents, id); index >= 0 {
+		en.node.removeParent(en.node.parents[index].Node())
+	}
 }
 
 func (en *expertNode) RemoveObserver(id Identifier) {
-	en.node.removeObserver(id)
+	if index := indexOfID(en.node.observers, id); index >= 0 {
+		en.node.removeObserver(en.node.observers[index].Node())
+	}
 }
 
 func (en *expertNode) Value() any {
@@ -213,7 +219,7 @@ func (en *expertNode) Value() any {
 }
 
 func (en *expertNode) ComputePseudoHeight() int {
-	return en.computePseudoHeightCached(make(map[Identifier]int), en.incr)
+	return en.computePseudoHeightCached(make(map[*Node]int), en.incr)
 }
 
 func (en *expertNode) nodeParents(n INode) []INode {
@@ -223,9 +229,9 @@ func (en *expertNode) nodeParents(n INode) []INode {
 	return nil
 }
 
-func (en *expertNode) computePseudoHeightCached(cache map[Identifier]int, n INode) int {
+func (en *expertNode) computePseudoHeightCached(cache map[*Node]int, n INode) int {
 	nn := n.Node()
-	if height, ok := cache[nn.ID()]; ok {
+	if height, ok := cache[nn]; ok {
 		return height
 	}
 
@@ -246,6 +252,6 @@ func (en *expertNode) computePseudoHeightCached(cache map[Identifier]int, n INod
 	} else {
 		finalHeight = maxParentHeight + 1
 	}
-	cache[nn.ID()] = finalHeight
+	cache[nn] = finalHeight
 	return finalHeight
 }
@@ -38,7 +38,10 @@ func (f *freezeIncr[T]) Parents() []INode {
 
 func (f *freezeIncr[T]) Node() *Node { return f.n }
 
-func (f *freezeIncr[T]) Value() T { return f.v }
+func (f *freezeIncr[T]) Value() T {
+	f.n.checkRead(f)
+	return f.v
+}
 
 func (f *freezeIncr[T]) String() string { return f.n.String() }
 
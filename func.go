@@ -38,7 +38,12 @@ type funcIncr[T any] struct {
 func (f *funcIncr[T]) Parents() []INode { return nil }
 
 func (f *funcIncr[T]) Node() *Node { return f.n }
-func (f *funcIncr[T]) Value() T    { return f.val }
+
+func (f *funcIncr[T]) Value() T {
+	f.n.checkRead(f)
+	return f.val
+}
+
 func (f *funcIncr[T]) Stabilize(ctx context.Context) error {
 	val, err := f.fn(ctx)
 	if err != nil {
@@ -1,10 +1,13 @@
 package incr
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"runtime"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -23,39 +26,52 @@ import (
 // an [Observer] before you can stabilize them.
 func New(opts ...GraphOption) *Graph {
 	options := GraphOptions{
-		MaxHeight:   DefaultMaxHeight,
 		Parallelism: runtime.NumCPU(),
 	}
 	for _, opt := range opts {
 		opt(&options)
 	}
+	if options.Clock == nil {
+		options.Clock = time.Now
+	}
 	return &Graph{
-		id:                       NewIdentifier(),
-		parallelism:              options.Parallelism,
-		stabilizationNum:         1,
-		status:                   StatusNotStabilizing,
-		nodes:                    allocateMapWithSize[Identifier, INode](options.PreallocateNodesSize),
-		observers:                allocateMapWithSize[Identifier, IObserver](options.PreallocateObserversSize),
-		sentinels:                allocateMapWithSize[Identifier, ISentinel](options.PreallocateSentinelsSize),
-		recomputeHeap:            newRecomputeHeap(options.MaxHeight),
-		adjustHeightsHeap:        newAdjustHeightsHeap(options.MaxHeight),
-		setDuringStabilization:   make(map[Identifier]INode),
-		handleAfterStabilization: make(map[Identifier][]func(context.Context)),
-		propagateInvalidityQueue: new(queue[INode]),
-	}
-}
-
-func allocateMapWithSize[K comparable, V any](size int) map[K]V {
+		id:                        NewIdentifier(),
+		parallelism:               options.Parallelism,
+		workerPool:                newWorkerPool(options.Parallelism),
+		concurrent:                options.Concurrent,
+		deterministic:             options.Deterministic,
+		observerSnapshots:         options.ObserverSnapshots,
+		onUnnecessaryRead:         options.OnUnnecessaryRead,
+		clock:                     options.Clock,
+		parallelSentinels:         options.ParallelSentinels,
+		reuseStabilizationContext: options.ReuseStabilizationContext,
+		stabilizationNum:          1,
+		status:                    StatusNotStabilizing,
+		nodes:                     allocateSliceWithSize[INode](options.PreallocateNodesSize),
+		observers:                 allocateSliceWithSize[IObserver](options.PreallocateObserversSize),
+		sentinels:                 allocateSliceWithSize[ISentinel](options.PreallocateSentinelsSize),
+		recomputeHeap:             newRecomputeHeap(options.MaxHeight),
+		adjustHeightsHeap:         newAdjustHeightsHeap(options.MaxHeight),
+		propagateInvalidityQueue:  new(queue[INode]),
+	}
+}
+
+func allocateSliceWithSize[V any](size int) []V {
 	if size > 0 {
-		return make(map[K]V, size)
+		return make([]V, 0, size)
 	}
-	return make(map[K]V)
+	return nil
 }
 
 // GraphOption mutates GraphOptions.
 type GraphOption func(*GraphOptions)
 
 // OptGraphMaxHeight sets the graph max recompute height.
+//
+// By default node heights are not limited, and the graph will grow its
+// internal height tracking as deeper nodes are added. Setting a max height
+// limits node heights to less than the max height; adding or binding a node
+// that would exceed the limit will return an error.
 func OptGraphMaxHeight(maxHeight int) func(*GraphOptions) {
 	return func(g *GraphOptions) {
 		g.MaxHeight = maxHeight
@@ -63,7 +79,8 @@ func OptGraphMaxHeight(maxHeight int) func(*GraphOptions) {
 }
 
 // OptGraphParallelism sets the parallelism factor, or said another way
-// the number of goroutines, to use when stabilizing using [Graph.ParallelStabilize].
+// the number of goroutines, to use when stabilizing using [Graph.ParallelStabilize]
+// or [Graph.DataflowStabilize].
 //
 // This will default to [runtime.NumCPU] if unset.
 func OptGraphParallelism(parallelism int) func(*GraphOptions) {
@@ -72,7 +89,127 @@ func OptGraphParallelism(parallelism int) func(*GraphOptions) {
 	}
 }
 
-// OptGraphPreallocateNodesSize preallocates the node tracking map within
+// OptGraphConcurrent sets if the graph should interlock public methods such that
+// they're safe to call from any goroutine.
+//
+// Specifically when the graph is concurrent:
+//   - Stabilization passes are serialized; calling [Graph.Stabilize] or [Graph.ParallelStabilize]
+//     while another goroutine is stabilizing the graph will wait for that stabilization to complete
+//     instead of returning [ErrAlreadyStabilizing].
+//   - [Observe], [ObserveIncr.Unobserve], [Sentinel], [SentinelIncr.Unwatch] and [MapNIncr.AddInput]
+//     wait for any in-progress stabilization to complete before changing the graph.
+//   - [Sentinel] and [MapNIncr.AddInput] change the graph immediately, on behalf of the stabilization,
+//     if they're called by a bind function with a node created in the scope the function is building.
+//     Otherwise they wait like the methods above, and as a result bind functions must not call them
+//     with only nodes that were created outside of their scope.
+//   - [VarIncr.Set] and [Graph.SetStale] take effect at the moment they acquire the graph's
+//     state lock (their linearization point). If the graph is not stabilizing at that moment
+//     the change is applied immediately and will be seen by the next stabilization, otherwise
+//     the change is staged and applied as the in-progress stabilization completes, and will be
+//     seen by the stabilization after that.
+//
+// Because the graph locks are not re-entrant, node functions and update handlers of a
+// concurrent graph must not call the other methods that change the graph structure (e.g. [Observe]),
+// nor stabilize the graph; setting vars and marking nodes stale from these functions is safe.
+//
+// Reading node values from other goroutines while the graph is stabilizing is not interlocked.
+func OptGraphConcurrent(concurrent bool) func(*GraphOptions) {
+	return func(g *GraphOptions) {
+		g.Concurrent = concurrent
+	}
+}
+
+// OptGraphDeterministic sets if the graph should recompute nodes and call update handlers
+// in a reproducible order, such that stabilizing graphs that are built and set the same way
+// produces the same sequence of side effects, e.g. for replays and golden tests.
+//
+// [Graph.Stabilize] recomputes the nodes at each height in the order they were added to the
+// recompute heap regardless of this option. Specifically when the graph is deterministic:
+//   - [Graph.ParallelStabilize] orders each height block by the order the nodes were added
+//     to the graph, such that the nodes are started, and the nodes recomputed on the
+//     calling goroutine are recomputed, in that order.
+//   - Update handlers are called after stabilization in ascending order of the height of
+//     their node, and then by the order the nodes were added to the graph, for each of
+//     the stabilization methods.
+//
+// Nodes that are recomputed concurrently by [Graph.ParallelStabilize] and [Graph.DataflowStabilize]
+// still finish in any order, so they should not depend on each other's side effects.
+//
+// The order the nodes were added to the graph reuses the positions of nodes that
+// were removed, so it's reproducible but not necessarily the order of creation.
+func OptGraphDeterministic(deterministic bool) func(*GraphOptions) {
+	return func(g *GraphOptions) {
+		g.Deterministic = deterministic
+	}
+}
+
+// OptGraphObserverSnapshots sets if the observers of the graph should publish a snapshot
+// of their value at the end of each stabilization, which can be read with [ObserveIncr.Snapshot]
+// from any goroutine without taking a graph lock, including while the graph is stabilizing.
+//
+// Publishing a snapshot allocates for each observer whose value changed, so
+// snapshots are not published by default.
+func OptGraphObserverSnapshots(enabled bool) func(*GraphOptions) {
+	return func(g *GraphOptions) {
+		g.ObserverSnapshots = enabled
+	}
+}
+
+// OptGraphOnUnnecessaryRead sets a handler that is called when the value of a node that
+// is computed by stabilization is read while the node is not necessary, that is it's not
+// observed and isn't a parent of a necessary node, and as a result its value may be
+// the zero value or out of date. Use [Peek] to read the value of such nodes.
+//
+// The handler is passed the node, and can log the read or panic, e.g. in tests. The
+// values of vars and of nodes created with [Return] are always current and are not checked.
+func OptGraphOnUnnecessaryRead(handler func(INode)) func(*GraphOptions) {
+	return func(g *GraphOptions) {
+		g.OnUnnecessaryRead = handler
+	}
+}
+
+// OptGraphClock sets the clock the graph uses to tell the time, which is read once at the
+// start of each stabilization, and is used by time based nodes such as sentinels with
+// a check interval (see [OptSentinelInterval]).
+//
+// If not provided, the graph uses [time.Now].
+func OptGraphClock(clock func() time.Time) func(*GraphOptions) {
+	return func(g *GraphOptions) {
+		g.Clock = clock
+	}
+}
+
+// OptGraphParallelSentinels sets if the graph should call the staleness functions of
+// the sentinels that are due to be checked in parallel, on the graph's pool of goroutines,
+// before the main recompute pass of each stabilization.
+//
+// This is useful if the staleness functions are slow, e.g. if they hash files, but
+// requires that the functions are safe to call concurrently.
+func OptGraphParallelSentinels(enabled bool) func(*GraphOptions) {
+	return func(g *GraphOptions) {
+		g.ParallelSentinels = enabled
+	}
+}
+
+// OptGraphReuseStabilizationContext sets if the graph should reuse the context it passes
+// to nodes and handlers between stabilizations, rather than allocating a new context
+// for each stabilization, such that steady state stabilizations don't allocate.
+//
+// The reused context is only valid during its stabilization; once the stabilization
+// completes it no longer carries the values or the cancellation of the context passed
+// to the stabilization, and it reports the number of the next stabilization once that
+// starts. As a result node functions and handlers must not retain the context past the
+// stabilization, e.g. in goroutines they start.
+//
+// The context is not reused if the context passed to the stabilization has a tracer,
+// or if the graph has nodes that retain the context, such as [Async] nodes.
+func OptGraphReuseStabilizationContext(enabled bool) func(*GraphOptions) {
+	return func(g *GraphOptions) {
+		g.ReuseStabilizationContext = enabled
+	}
+}
+
+// OptGraphPreallocateNodesSize preallocates the node tracking list within
 // the graph with a given size number of elements for items.
 //
 // If not provided, no size for elements will be preallocated.
@@ -82,7 +219,7 @@ func OptGraphPreallocateNodesSize(size int) func(*GraphOptions) {
 	}
 }
 
-// OptGraphPreallocateObserversSize preallocates the observer tracking map within
+// OptGraphPreallocateObserversSize preallocates the observer tracking list within
 // the graph with a given size number of elements for items.
 //
 // If not provided, no size for elements will be preallocated.
@@ -92,7 +229,7 @@ func OptGraphPreallocateObserversSize(size int) func(*GraphOptions) {
 	}
 }
 
-// OptGraphPreallocateSentinelsSize preallocates the sentinel tracking map within
+// OptGraphPreallocateSentinelsSize preallocates the sentinel tracking list within
 // the graph with a given size number of elements for items.
 //
 // If not provided, no size for elements will be preallocated.
@@ -104,16 +241,26 @@ func OptGraphPreallocateSentinelsSize(size int) func(*GraphOptions) {
 
 // GraphOptions are options for graphs.
 type GraphOptions struct {
-	MaxHeight                int
-	Parallelism              int
-	PreallocateNodesSize     int
-	PreallocateObserversSize int
-	PreallocateSentinelsSize int
+	MaxHeight                 int
+	Parallelism               int
+	Concurrent                bool
+	Deterministic             bool
+	ObserverSnapshots         bool
+	OnUnnecessaryRead         func(INode)
+	Clock                     func() time.Time
+	ParallelSentinels         bool
+	ReuseStabilizationContext bool
+	PreallocateNodesSize      int
+	PreallocateObserversSize  int
+	PreallocateSentinelsSize  int
 }
 
 const (
-	// DefaultMaxHeight is the default maximum height that can
-	// be tracked in the recompute heap.
+	// DefaultMaxHeight is the number of heights that are preallocated
+	// in the recompute heap and the adjust heights heap.
+	//
+	// Heights above this are allocated as they're needed, and are only
+	// limited if a max height is set with [OptGraphMaxHeight].
 	DefaultMaxHeight = 256
 )
 
@@ -136,22 +283,84 @@ type Graph struct {
 	// parallelism is the degree of parallelism used when processing nodes
 	// with the [parallelBatch] iterator.
 	parallelism int
+	// workerPool is the pool of goroutines used by parallel stabilization.
+	workerPool *workerPool
+	// parallelNodeCost is the moving average of the time taken to
+	// recompute a node during parallel stabilization, and is used
+	// to decide if a height block should be processed in parallel.
+	parallelNodeCost time.Duration
+	// parallelBatchBuffer is reused to hold each height
+	// block during parallel stabilization.
+	parallelBatchBuffer []INode
+	// immediateRecomputeBuffer is reused to hold the always
+	// nodes recomputed during sequential stabilization.
+	immediateRecomputeBuffer []INode
+	// reuseStabilizationContext indicates that the context passed to nodes
+	// should be reused between stabilizations if it can't be retained.
+	reuseStabilizationContext bool
+	// stabilizationCtx is the context that is reused between stabilizations
+	// if the graph reuses stabilization contexts.
+	stabilizationCtx *stabilizationContext
+	// retainsContexts is set if the graph has nodes that retain
+	// the stabilization context past the stabilization (e.g. [Async]).
+	retainsContexts atomic.Bool
+
+	// concurrent indicates that public methods should interlock
+	// such that they can be called from any goroutine.
+	concurrent bool
+	// stabilizeMu serializes stabilization passes and changes
+	// to the graph structure if the graph is concurrent.
+	stabilizeMu sync.Mutex
+	// stateMu interlocks changes to the graph status with sets
+	// and stale marks if the graph is concurrent.
+	stateMu sync.Mutex
+
+	// deterministic indicates that nodes should be recomputed and
+	// update handlers called in a reproducible order.
+	deterministic bool
+	// observerSnapshots indicates that observers should publish
+	// snapshots of their values at the end of each stabilization.
+	observerSnapshots bool
+	// onUnnecessaryRead is an optional handler called when the
+	// value of a node that is not necessary is read.
+	onUnnecessaryRead func(INode)
+	// clock returns the current time, and is read at the start of each stabilization.
+	clock func() time.Time
+	// parallelSentinels indicates that sentinels that are due to be checked should
+	// be checked in parallel before the main recompute pass.
+	parallelSentinels bool
+	// dueSentinelsBuffer is reused between stabilizations to hold
+	// the sentinels that are due to be checked in parallel.
+	dueSentinelsBuffer []sentinelChecks
+	// lastStabilizedNum and lastStabilizedTime are the stabilization number and the
+	// start time, in unix nanoseconds, of the last stabilization that completed
+	// without an error, and are accessed atomically.
+	lastStabilizedNum  uint64
+	lastStabilizedTime int64
+
+	// handlesMu interlocks access to nextHandle and freeHandles
+	handlesMu sync.Mutex
+	// nextHandle is the next node handle that hasn't been used yet.
+	nextHandle uint32
+	// freeHandles are the handles of nodes that have been removed
+	// from the graph, and are reused before new handles are used.
+	freeHandles []uint32
 
 	// nodesMu interlocks access to nodes
 	nodesMu sync.Mutex
 	// observed are the nodes that the graph currently observes
-	// organized by node id.
-	nodes map[Identifier]INode
+	// indexed by node handle.
+	nodes []INode
 
 	// observersMu interlocks access to observers
 	observersMu sync.Mutex
-	// observers hold references to observers organized by node id.
-	observers map[Identifier]IObserver
+	// observers hold references to observers indexed by node handle.
+	observers []IObserver
 
 	// sentinelsMu interlocks access to sentinels
 	sentinelsMu sync.Mutex
-	// sentinels hold references to sentinels organized by node id.
-	sentinels map[Identifier]ISentinel
+	// sentinels hold references to sentinels indexed by node handle.
+	sentinels []ISentinel
 
 	// recomputeHeap is the heap of nodes to be processed
 	// organized by pseudo-height. The recompute heap
@@ -163,12 +372,20 @@ type Graph struct {
 	// setDuringStabilizationMu interlocks acces to setDuringStabilization
 	setDuringStabilizationMu sync.Mutex
 	// setDuringStabilization is a list of nodes that were
-	// set during stabilization
-	setDuringStabilization map[Identifier]INode
-
-	// handleAfterStabilization is a list of update
-	// handlers that need to run after stabilization is done.
-	handleAfterStabilization map[Identifier][]func(context.Context)
+	// set during stabilization in the order they were set
+	setDuringStabilization      []stagedVar
+	setDuringStabilizationIndex map[*Node]int
+	// staleDuringStabilization is a list of nodes that were marked stale
+	// during stabilization of a concurrent graph in the order they were marked
+	staleDuringStabilization      []INode
+	staleDuringStabilizationIndex map[*Node]int
+
+	// handleAfterStabilization is a list of update handlers that
+	// need to run after stabilization is done indexed by node handle.
+	handleAfterStabilization [][]func(context.Context)
+	// handleAfterStabilizationHandles are the node handles with update
+	// handlers in handleAfterStabilization in the order they were added.
+	handleAfterStabilizationHandles []uint32
 	// handleAfterStabilizationMu coordinates access to handleAfterStabilization
 	handleAfterStabilizationMu sync.Mutex
 
@@ -183,7 +400,12 @@ type Graph struct {
 	// - StatusRunningUpdateHandlers
 	status int32
 	// stabilizationStarted is the time of the stabilization pass currently in progress
+	// as told by the graph clock.
 	stabilizationStarted time.Time
+	// stabilizationStartedMonotonic is the time of the stabilization pass currently in
+	// progress as told by [time.Now], and is used to measure how long the pass takes
+	// regardless of the graph clock.
+	stabilizationStartedMonotonic time.Time
 	// numNodes are the total number of nodes found during
 	// discovery and is typically used for testing
 	numNodes uint64
@@ -195,6 +417,19 @@ type Graph struct {
 	// that have been changed in the graph's history
 	// and is typically used in testing
 	numNodesChanged uint64
+	// numNodesCutoff is the total number of nodes
+	// that have been recomputed but cut off in the
+	// graph's history
+	numNodesCutoff uint64
+	// numBindScopesCreated is the total number of bind
+	// right-hand side scopes created in the graph's history
+	numBindScopesCreated uint64
+	// numBindScopesDestroyed is the total number of bind
+	// right-hand side scopes destroyed in the graph's history
+	numBindScopesDestroyed uint64
+	// report is the report of the stabilization in progress
+	// if it was started with [Graph.StabilizeWithReport].
+	report *StabilizationReport
 
 	// metadata is extra data you can add to the graph instance and
 	// manage yourself.
@@ -206,6 +441,23 @@ type Graph struct {
 	// onStabilizationEnd are optional hooks called when stabilization ends.
 	onStabilizationEnd []func(context.Context, time.Time, error)
 
+	// onSetStale are optional hooks called when a node is set or marked stale.
+	//
+	// If the graph is concurrent, this list is guarded by the state lock, and
+	// is replaced rather than modified in place when a handler is removed.
+	onSetStale []setStaleHandler
+	// onSetStaleID is the identifier of the most recently added set stale handler.
+	onSetStaleID uint64
+
+	// onObserversChanged are optional hooks called after stabilization
+	// with the observers whose values changed.
+	onObserversChanged []func(context.Context, []IObserver)
+	// changedObserversMu interlocks access to changedObservers
+	changedObserversMu sync.Mutex
+	// changedObservers are the observers of the nodes that changed during
+	// stabilization, and are only tracked if onObserversChanged is set.
+	changedObservers []IObserver
+
 	propagateInvalidityQueue *queue[INode]
 }
 
@@ -242,7 +494,7 @@ func (graph *Graph) IsStabilizing() bool {
 // IsObserving returns if a graph is observing a given node.
 func (graph *Graph) Has(gn INode) (ok bool) {
 	graph.nodesMu.Lock()
-	_, ok = graph.nodes[gn.Node().id]
+	ok = hasHandle(graph.nodes, gn.Node())
 	graph.nodesMu.Unlock()
 	return
 }
@@ -250,7 +502,7 @@ func (graph *Graph) Has(gn INode) (ok bool) {
 // HasObserver returns if a graph has a given observer.
 func (graph *Graph) HasObserver(on IObserver) (ok bool) {
 	graph.observersMu.Lock()
-	_, ok = graph.observers[on.Node().id]
+	ok = hasHandle(graph.observers, on.Node())
 	graph.observersMu.Unlock()
 	return
 }
@@ -258,7 +510,7 @@ func (graph *Graph) HasObserver(on IObserver) (ok bool) {
 // HasSentinel returns if a graph has a given sentinel.
 func (graph *Graph) HasSentinel(sn ISentinel) (ok bool) {
 	graph.sentinelsMu.Lock()
-	_, ok = graph.sentinels[sn.Node().id]
+	ok = hasHandle(graph.sentinels, sn.Node())
 	graph.sentinelsMu.Unlock()
 	return
 }
@@ -273,10 +525,114 @@ func (graph *Graph) OnStabilizationEnd(handler func(context.Context, time.Time,
 	graph.onStabilizationEnd = append(graph.onStabilizationEnd, handler)
 }
 
+// OnSetStale adds a handler that is called when a var is set or
+// a node is marked stale with [Graph.SetStale].
+//
+// Handlers are called on the goroutine that set the node, after the
+// change has been applied or staged, and should not block.
+//
+// The returned function removes the handler, and is safe to call more than once.
+func (graph *Graph) OnSetStale(handler func(INode)) (remove func()) {
+	if graph.concurrent {
+		graph.stateMu.Lock()
+		defer graph.stateMu.Unlock()
+	}
+	graph.onSetStaleID++
+	id := graph.onSetStaleID
+	graph.onSetStale = append(graph.onSetStale, setStaleHandler{id: id, fn: handler})
+	return func() {
+		graph.removeSetStaleHandler(id)
+	}
+}
+
+func (graph *Graph) removeSetStaleHandler(id uint64) {
+	if graph.concurrent {
+		graph.stateMu.Lock()
+		defer graph.stateMu.Unlock()
+	}
+	index := slices.IndexFunc(graph.onSetStale, func(h setStaleHandler) bool { return h.id == id })
+	if index < 0 {
+		return
+	}
+	// the handlers are read outside the state lock once
+	// they're captured, so we can't modify the list in place.
+	graph.onSetStale = slices.Delete(slices.Clone(graph.onSetStale), index, index+1)
+}
+
+// setStaleHandler is a handler added with [Graph.OnSetStale].
+type setStaleHandler struct {
+	id uint64
+	fn func(INode)
+}
+
+// OnObserversChanged adds a handler that is called after each stabilization with the observers
+// whose values changed, e.g. to invalidate downstream caches for the observed values.
+//
+// An observer's value changed if its observed node changed during the stabilization,
+// and if the observer was created with [OptObserveEqual], the value is not equal to
+// the value the observer had when it last changed.
+//
+// Handlers are called after the update handlers, and are not called if no observers changed.
+func (graph *Graph) OnObserversChanged(handler func(context.Context, []IObserver)) {
+	graph.onObserversChanged = append(graph.onObserversChanged, handler)
+}
+
 // Node helpers
 
 // SetStale sets a node as stale.
+//
+// If the graph is concurrent and is stabilizing, the node will be
+// marked stale when the stabilization completes.
 func (graph *Graph) SetStale(gn INode) {
+	graph.markStale(gn, gn)
+}
+
+// setStaleIfNecessary sets a node as stale like [Graph.SetStale], except that
+// the mark is dropped if the node is not necessary when the mark is applied.
+//
+// Nodes that mark themselves stale from other goroutines (e.g. [Async]) use this,
+// as they can stop being necessary before the mark is applied.
+func (graph *Graph) setStaleIfNecessary(gn INode) {
+	graph.markStale(staleIfNecessary{gn}, gn)
+}
+
+// staleIfNecessary is a stale mark for a node that is dropped
+// if the node is not necessary when the mark is applied.
+type staleIfNecessary struct {
+	INode
+}
+
+// markStale applies or stages a given stale mark for a node,
+// and calls the set stale handlers with the node.
+func (graph *Graph) markStale(mark, gn INode) {
+	var handlers []setStaleHandler
+	if graph.concurrent {
+		graph.stateMu.Lock()
+		if atomic.LoadInt32(&graph.status) != StatusNotStabilizing {
+			graph.setDuringStabilizationMu.Lock()
+			graph.staleDuringStabilization, graph.staleDuringStabilizationIndex = addIndexed(graph.staleDuringStabilization, graph.staleDuringStabilizationIndex, mark)
+			graph.setDuringStabilizationMu.Unlock()
+		} else {
+			graph.setStale(mark)
+		}
+		handlers = graph.onSetStale
+		graph.stateMu.Unlock()
+	} else {
+		graph.setStale(mark)
+		handlers = graph.onSetStale
+	}
+	for _, handler := range handlers {
+		handler.fn(gn)
+	}
+}
+
+func (graph *Graph) setStale(gn INode) {
+	if typed, ok := gn.(staleIfNecessary); ok {
+		if !typed.Node().isNecessary() {
+			return
+		}
+		gn = typed.INode
+	}
 	n := gn.Node()
 	n.setAt = graph.stabilizationNum
 	if gn.Node().heightInRecomputeHeap == HeightUnset {
@@ -284,6 +640,42 @@ func (graph *Graph) SetStale(gn INode) {
 	}
 }
 
+// lockStructure acquires the locks required to change the structure
+// of the graph if the graph is concurrent.
+func (graph *Graph) lockStructure() {
+	if graph.concurrent {
+		graph.stabilizeMu.Lock()
+		graph.stateMu.Lock()
+	}
+}
+
+// unlockStructure releases the locks acquired by [Graph.lockStructure].
+func (graph *Graph) unlockStructure() {
+	if graph.concurrent {
+		graph.stateMu.Unlock()
+		graph.stabilizeMu.Unlock()
+	}
+}
+
+// lockStructureUnlessBuilding acquires the locks required to change the structure
+// of the graph if the graph is concurrent, returning if the locks were acquired.
+//
+// The locks are not acquired if any of the given scopes is being built by a bind
+// function, as the change is then made on behalf of the stabilization, which already
+// holds the locks; other callers wait for any in-progress stabilization to complete.
+func (graph *Graph) lockStructureUnlessBuilding(scopes ...Scope) (locked bool) {
+	if !graph.concurrent {
+		return false
+	}
+	for _, scope := range scopes {
+		if scope != nil && scope.isScopeBuilding() {
+			return false
+		}
+	}
+	graph.lockStructure()
+	return true
+}
+
 //
 // Scope interface methods
 //
@@ -291,9 +683,11 @@ func (graph *Graph) SetStale(gn INode) {
 func (graph *Graph) isTopScope() bool       { return true }
 func (graph *Graph) isScopeValid() bool     { return true }
 func (graph *Graph) isScopeNecessary() bool { return true }
+func (graph *Graph) isScopeBuilding() bool  { return false }
 func (graph *Graph) scopeGraph() *Graph     { return graph }
 func (graph *Graph) scopeHeight() int       { return HeightUnset }
 func (graph *Graph) addScopeNode(_ INode)   {}
+func (graph *Graph) Defer(_ func())         {}
 func (graph *Graph) String() string         { return fmt.Sprintf("{graph:%s}", graph.id.Short()) }
 
 //
@@ -313,6 +707,7 @@ func (graph *Graph) invalidateNode(node INode) {
 		nn.height = node.Node().createdIn.scopeHeight() + 1
 	}
 	nn.maybeInvalidate()
+	nn.maybeRelease()
 	nn.valid = false
 	for _, child := range node.Node().children {
 		graph.propagateInvalidityQueue.push(child)
@@ -329,8 +724,8 @@ func (graph *Graph) removeParents(child INode) {
 }
 
 func (graph *Graph) unlink(child, parent INode) {
-	child.Node().removeParent(parent.Node().id)
-	parent.Node().removeChild(child.Node().id)
+	child.Node().removeParent(parent.Node())
+	parent.Node().removeChild(child.Node())
 }
 
 func (graph *Graph) removeParent(child, parent INode) {
@@ -345,6 +740,7 @@ func (graph *Graph) checkIfUnnecessary(parent INode) {
 }
 
 func (graph *Graph) becameUnnecessary(parent INode) {
+	parent.Node().maybeRelease()
 	graph.removeParents(parent)
 	graph.removeNode(parent)
 }
@@ -380,10 +776,10 @@ func (graph *Graph) addChild(child, parent INode) error {
 
 func (graph *Graph) changeParent(child, oldParent, newParent INode) error {
 	if oldParent != nil && newParent != nil {
-		if oldParent.Node().id == newParent.Node().id {
+		if oldParent.Node() == newParent.Node() {
 			return nil
 		}
-		oldParent.Node().removeChild(child.Node().id)
+		oldParent.Node().removeChild(child.Node())
 		oldParent.Node().forceNecessary = true
 		if err := graph.addChild(child, newParent); err != nil {
 			return err
@@ -397,11 +793,28 @@ func (graph *Graph) changeParent(child, oldParent, newParent INode) error {
 	}
 
 	// newParent is nil
-	oldParent.Node().removeChild(child.Node().id)
+	oldParent.Node().removeChild(child.Node())
 	graph.checkIfUnnecessary(oldParent)
 	return nil
 }
 
+// unchangeParent undoes a call to [Graph.changeParent] with the same arguments that
+// returned an error, linking the child to the old parent again, and unlinking it from
+// the new parent.
+func (graph *Graph) unchangeParent(child, oldParent, newParent INode) {
+	if oldParent != nil && newParent != nil && oldParent.Node() == newParent.Node() {
+		return
+	}
+	if oldParent != nil {
+		graph.link(child, oldParent)
+		oldParent.Node().forceNecessary = false
+	}
+	if newParent != nil {
+		graph.unlink(child, newParent)
+		graph.checkIfUnnecessary(newParent)
+	}
+}
+
 func (graph *Graph) propagateInvalidity() {
 	for graph.propagateInvalidityQueue.len() > 0 {
 		node, _ := graph.propagateInvalidityQueue.pop()
@@ -471,13 +884,12 @@ func (graph *Graph) addNode(n INode) {
 	defer graph.nodesMu.Unlock()
 
 	gnn := n.Node()
-	_, graphAlreadyHasNode := graph.nodes[gnn.id]
-	if graphAlreadyHasNode {
+	if hasHandle(graph.nodes, gnn) {
 		return
 	}
 	graph.numNodes++
 	gnn.initializeFrom(n)
-	graph.nodes[gnn.id] = n
+	graph.nodes = setHandle(graph.nodes, graph.ensureHandle(gnn), n)
 }
 
 func (graph *Graph) addObserver(on IObserver) {
@@ -485,13 +897,12 @@ func (graph *Graph) addObserver(on IObserver) {
 	defer graph.observersMu.Unlock()
 
 	onn := on.Node()
-	_, graphAlreadyHasObserver := graph.observers[onn.id]
-	if graphAlreadyHasObserver {
+	if hasHandle(graph.observers, onn) {
 		return
 	}
 	graph.numNodes++
 	onn.initializeFrom(on)
-	graph.observers[onn.id] = on
+	graph.observers = setHandle(graph.observers, graph.ensureHandle(onn), on)
 }
 
 func (graph *Graph) addSentinel(sn ISentinel) {
@@ -499,32 +910,37 @@ func (graph *Graph) addSentinel(sn ISentinel) {
 	defer graph.sentinelsMu.Unlock()
 
 	snn := sn.Node()
-	_, graphAlreadyHasSentinel := graph.sentinels[snn.id]
-	if graphAlreadyHasSentinel {
+	if hasHandle(graph.sentinels, snn) {
 		return
 	}
 	graph.numNodes++
 	snn.initializeFrom(sn)
-	graph.sentinels[snn.id] = sn
+	graph.sentinels = setHandle(graph.sentinels, graph.ensureHandle(snn), sn)
 }
 
 func (graph *Graph) removeObserver(on IObserver) {
 	graph.observersMu.Lock()
-	delete(graph.observers, on.Node().id)
+	if hasHandle(graph.observers, on.Node()) {
+		graph.observers[on.Node().handle] = nil
+	}
 	graph.observersMu.Unlock()
 	graph.zeroNode(on)
 }
 
 func (graph *Graph) removeSentinel(sn ISentinel) {
 	graph.sentinelsMu.Lock()
-	delete(graph.sentinels, sn.Node().id)
+	if hasHandle(graph.sentinels, sn.Node()) {
+		graph.sentinels[sn.Node().handle] = nil
+	}
 	graph.sentinelsMu.Unlock()
 	graph.zeroNode(sn)
 }
 
 func (graph *Graph) removeNode(gn INode) {
 	graph.nodesMu.Lock()
-	delete(graph.nodes, gn.Node().id)
+	if hasHandle(graph.nodes, gn.Node()) {
+		graph.nodes[gn.Node().handle] = nil
+	}
 	graph.nodesMu.Unlock()
 	graph.zeroNode(gn)
 }
@@ -539,8 +955,11 @@ func (graph *Graph) zeroNode(n INode) {
 	nn := n.Node()
 
 	graph.handleAfterStabilizationMu.Lock()
-	delete(graph.handleAfterStabilization, nn.ID())
+	if int(nn.handle) < len(graph.handleAfterStabilization) {
+		graph.handleAfterStabilization[nn.handle] = nil
+	}
 	graph.handleAfterStabilizationMu.Unlock()
+	graph.releaseHandle(nn)
 
 	nn.setAt = 0
 	nn.changedAt = 0
@@ -550,8 +969,11 @@ func (graph *Graph) zeroNode(n INode) {
 	nn.valid = true
 
 	nn.parents = nil
+	nn.parentsIndex = nil
 	nn.children = nil
+	nn.childrenIndex = nil
 	nn.observers = nil
+	nn.observersIndex = nil
 
 	// TODO (wc): why can't i zero these out?
 	// nn.createdIn = nil
@@ -570,7 +992,7 @@ func (graph *Graph) observeNode(o IObserver, input INode) error {
 		}
 	}
 	graph.handleAfterStabilizationMu.Lock()
-	graph.handleAfterStabilization[o.Node().id] = o.Node().onUpdateHandlers
+	graph.addHandleAfterStabilizationUnsafe(o.Node(), o.Node().onUpdateHandlers)
 	graph.handleAfterStabilizationMu.Unlock()
 	return nil
 }
@@ -587,13 +1009,13 @@ func (graph *Graph) watchNode(sn ISentinel, input INode) error {
 
 func (graph *Graph) unobserveNode(o IObserver, input INode) {
 	graph.removeObserver(o)
-	input.Node().removeObserver(o.Node().id)
+	input.Node().removeObserver(o.Node())
 	graph.checkIfUnnecessary(input)
 }
 
 func (graph *Graph) unwatchNode(sn ISentinel, input INode) {
 	graph.removeSentinel(sn)
-	input.Node().removeSentinel(sn.Node().id)
+	input.Node().removeSentinel(sn.Node())
 	graph.unlink(input, sn)
 }
 
@@ -602,6 +1024,13 @@ func (graph *Graph) unwatchNode(sn ISentinel, input INode) {
 //
 
 func (graph *Graph) ensureNotStabilizing(ctx context.Context) error {
+	if graph.concurrent {
+		// concurrent graphs wait for the in-progress stabilization
+		// to complete rather than returning an error; the lock
+		// is released by [Graph.stabilizeEnd].
+		graph.stabilizeMu.Lock()
+		return nil
+	}
 	if atomic.LoadInt32(&graph.status) != StatusNotStabilizing {
 		TracePrintf(ctx, "stabilize; already stabilizing, cannot continue")
 		return ErrAlreadyStabilizing
@@ -610,31 +1039,86 @@ func (graph *Graph) ensureNotStabilizing(ctx context.Context) error {
 }
 
 func (graph *Graph) stabilizeStart(ctx context.Context) context.Context {
-	atomic.StoreInt32(&graph.status, StatusStabilizing)
+	if graph.concurrent {
+		graph.stateMu.Lock()
+		atomic.StoreInt32(&graph.status, StatusStabilizing)
+		graph.stateMu.Unlock()
+	} else {
+		atomic.StoreInt32(&graph.status, StatusStabilizing)
+	}
 	for _, handler := range graph.onStabilizationStart {
 		handler(ctx)
 	}
-	graph.stabilizationStarted = time.Now()
-	ctx = WithStabilizationNumber(ctx, graph.stabilizationNum)
+	graph.stabilizationStarted = graph.clock()
+	graph.stabilizationStartedMonotonic = time.Now()
+	ctx = graph.stabilizationContext(ctx)
 	TracePrintln(ctx, "stabilization starting")
 	return ctx
 }
 
+// stabilizationContext returns the context for a stabilization with the current stabilization number.
+//
+// A new context is returned for each stabilization, unless the graph reuses stabilization
+// contexts (see [OptGraphReuseStabilizationContext]) and the context can't be retained past
+// the stabilization, i.e. the parent context has no tracer and the graph has no nodes
+// that retain contexts (e.g. [Async]), in which case the context is kept by the graph.
+func (graph *Graph) stabilizationContext(ctx context.Context) context.Context {
+	if !graph.reuseStabilizationContext || GetTracer(ctx) != nil || graph.retainsContexts.Load() {
+		return &stabilizationContext{Context: ctx, stabilizationNum: graph.stabilizationNum}
+	}
+	if graph.stabilizationCtx == nil {
+		graph.stabilizationCtx = new(stabilizationContext)
+	}
+	graph.stabilizationCtx.Context = ctx
+	graph.stabilizationCtx.stabilizationNum = graph.stabilizationNum
+	return graph.stabilizationCtx
+}
+
 func (graph *Graph) stabilizeEnd(ctx context.Context, err error) {
+	var stateLocked bool
 	defer func() {
+		if graph.stabilizationCtx != nil {
+			// release the parent context so that it isn't kept by the graph.
+			graph.stabilizationCtx.Context = context.Background()
+		}
 		graph.stabilizationStarted = time.Time{}
+		graph.stabilizationStartedMonotonic = time.Time{}
 		atomic.StoreInt32(&graph.status, StatusNotStabilizing)
+		if stateLocked {
+			graph.stateMu.Unlock()
+		}
+		if graph.concurrent {
+			graph.stabilizeMu.Unlock()
+		}
 	}()
 	for _, handler := range graph.onStabilizationEnd {
 		handler(ctx, graph.stabilizationStarted, err)
 	}
-	if err != nil {
-		TraceErrorf(ctx, "stabilization error: %v", err)
-		TracePrintf(ctx, "stabilization failed (%v elapsed)", time.Since(graph.stabilizationStarted).Round(time.Microsecond))
-	} else {
-		TracePrintf(ctx, "stabilization complete (%v elapsed)", time.Since(graph.stabilizationStarted).Round(time.Microsecond))
+	// check for a tracer first so the trace arguments
+	// aren't allocated if they won't be printed.
+	if GetTracer(ctx) != nil {
+		if err != nil {
+			TraceErrorf(ctx, "stabilization error: %v", err)
+			TracePrintf(ctx, "stabilization failed (%v elapsed)", time.Since(graph.stabilizationStartedMonotonic).Round(time.Microsecond))
+		} else {
+			TracePrintf(ctx, "stabilization complete (%v elapsed)", time.Since(graph.stabilizationStartedMonotonic).Round(time.Microsecond))
+		}
+	}
+	if err == nil {
+		atomic.StoreUint64(&graph.lastStabilizedNum, graph.stabilizationNum)
+		atomic.StoreInt64(&graph.lastStabilizedTime, graph.stabilizationStarted.UnixNano())
+	}
+	if graph.observerSnapshots && err == nil {
+		graph.stabilizeEndPublishSnapshots()
 	}
 	graph.stabilizeEndRunUpdateHandlers(ctx)
+	graph.stabilizeEndRunObserversChanged(ctx)
+	if graph.concurrent {
+		// hold the state lock through the status change so that sets
+		// are either staged and handled here, or applied after.
+		graph.stateMu.Lock()
+		stateLocked = true
+	}
 	graph.stabilizationNum++
 	graph.stabilizeEndHandleSetDuringStabilization(ctx)
 }
@@ -643,10 +1127,18 @@ func (graph *Graph) stabilizeEndHandleSetDuringStabilization(ctx context.Context
 	graph.setDuringStabilizationMu.Lock()
 	defer graph.setDuringStabilizationMu.Unlock()
 	for _, n := range graph.setDuringStabilization {
-		_ = n.Node().maybeStabilize(ctx)
-		graph.SetStale(n)
+		n.applySetDuringStabilization()
+		graph.setStale(n)
 	}
 	clear(graph.setDuringStabilization)
+	clear(graph.setDuringStabilizationIndex)
+	graph.setDuringStabilization = graph.setDuringStabilization[:0]
+	for _, n := range graph.staleDuringStabilization {
+		graph.setStale(n)
+	}
+	clear(graph.staleDuringStabilization)
+	clear(graph.staleDuringStabilizationIndex)
+	graph.staleDuringStabilization = graph.staleDuringStabilization[:0]
 }
 
 func (graph *Graph) stabilizeEndRunUpdateHandlers(ctx context.Context) {
@@ -654,24 +1146,181 @@ func (graph *Graph) stabilizeEndRunUpdateHandlers(ctx context.Context) {
 	defer graph.handleAfterStabilizationMu.Unlock()
 
 	atomic.StoreInt32(&graph.status, StatusRunningUpdateHandlers)
-	if len(graph.handleAfterStabilization) > 0 {
+	if graph.deterministic {
+		graph.sortHandleAfterStabilizationHandles()
+	}
+	if len(graph.handleAfterStabilizationHandles) > 0 {
 		TracePrintln(ctx, "stabilization calling user update handlers starting")
 		defer func() {
 			TracePrintln(ctx, "stabilization calling user update handlers complete")
 		}()
 	}
-	for _, uhGroup := range graph.handleAfterStabilization {
+	for _, handle := range graph.handleAfterStabilizationHandles {
+		// a handle can be listed more than once if the node it belonged to was
+		// removed and the handle was reused, so the handlers are cleared as they're run.
+		uhGroup := graph.handleAfterStabilization[handle]
+		graph.handleAfterStabilization[handle] = nil
 		for _, uh := range uhGroup {
 			uh(ctx)
 		}
 	}
-	clear(graph.handleAfterStabilization)
+	graph.handleAfterStabilizationHandles = graph.handleAfterStabilizationHandles[:0]
+}
+
+// hasStaleAncestor returns if a node or any of its ancestors is in the recompute heap (other
+// than to be recomputed as an always node), has a value staged during stabilization, or was
+// marked stale during stabilization.
+//
+// The graph must not be stabilizing.
+func (graph *Graph) hasStaleAncestor(n INode) bool {
+	graph.setDuringStabilizationMu.Lock()
+	markedStale := make(map[*Node]struct{}, len(graph.staleDuringStabilization))
+	for _, sn := range graph.staleDuringStabilization {
+		markedStale[sn.Node()] = struct{}{}
+	}
+	graph.setDuringStabilizationMu.Unlock()
+
+	graph.recomputeHeap.mu.Lock()
+	defer graph.recomputeHeap.mu.Unlock()
+	seen := make(map[*Node]struct{})
+	queue := []INode{n}
+	for len(queue) > 0 {
+		next := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		nn := next.Node()
+		if _, ok := seen[nn]; ok {
+			continue
+		}
+		seen[nn] = struct{}{}
+		// always nodes (including sentinels) are added back to the recompute heap
+		// after each stabilization, so they're only stale if they haven't been
+		// recomputed yet or were marked stale since they were last recomputed.
+		if nn.heightInRecomputeHeap != HeightUnset && (!nn.always || nn.recomputedAt == 0 || nn.setAt > nn.recomputedAt) {
+			return true
+		}
+		if _, ok := markedStale[nn]; ok {
+			return true
+		}
+		if typed, ok := next.(stagedVar); ok && typed.hasSetDuringStabilization() {
+			return true
+		}
+		queue = append(queue, nn.parents...)
+	}
+	return false
+}
+
+// stabilizeEndPublishSnapshots publishes the snapshots of
+// the observers whose values changed since they last published.
+func (graph *Graph) stabilizeEndPublishSnapshots() {
+	graph.observersMu.Lock()
+	defer graph.observersMu.Unlock()
+	for _, o := range graph.observers {
+		if typed, ok := o.(observerSnapshot); ok {
+			typed.publishSnapshot(graph.stabilizationNum)
+		}
+	}
+}
+
+func (graph *Graph) stabilizeEndRunObserversChanged(ctx context.Context) {
+	if len(graph.changedObservers) == 0 {
+		return
+	}
+	stabilizationNum := graph.stabilizationNum
+	var changed []IObserver
+	for _, o := range graph.changedObservers {
+		if typed, ok := o.(observerChange); ok && !typed.hasObservedChange(stabilizationNum) {
+			continue
+		}
+		changed = append(changed, o)
+	}
+	clear(graph.changedObservers)
+	graph.changedObservers = graph.changedObservers[:0]
+	if len(changed) == 0 {
+		return
+	}
+	if graph.deterministic {
+		slices.SortFunc(changed, compareHandles)
+	}
+	for _, handler := range graph.onObserversChanged {
+		handler(ctx, changed)
+	}
+}
+
+// sortHandleAfterStabilizationHandles sorts the handles with update handlers
+// by the height of their node, and then by handle.
+//
+// Observers are sorted after the nodes as they're logically children of the nodes they observe.
+func (graph *Graph) sortHandleAfterStabilizationHandles() {
+	graph.nodesMu.Lock()
+	defer graph.nodesMu.Unlock()
+	graph.observersMu.Lock()
+	defer graph.observersMu.Unlock()
+	height := func(handle uint32) int {
+		if int(handle) < len(graph.nodes) && graph.nodes[handle] != nil {
+			return graph.nodes[handle].Node().height
+		}
+		if int(handle) < len(graph.observers) && graph.observers[handle] != nil {
+			return math.MaxInt
+		}
+		return HeightUnset
+	}
+	slices.SortFunc(graph.handleAfterStabilizationHandles, func(a, b uint32) int {
+		if c := cmp.Compare(height(a), height(b)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+}
+
+// addHandleAfterStabilizationUnsafe sets the update handlers to run after stabilization
+// for a given node, replacing any handlers that were previously set for the node.
+//
+// The handleAfterStabilizationMu must be held when calling this method.
+func (graph *Graph) addHandleAfterStabilizationUnsafe(n *Node, handlers []func(context.Context)) {
+	handle := graph.ensureHandle(n)
+	if int(handle) >= len(graph.handleAfterStabilization) || graph.handleAfterStabilization[handle] == nil {
+		graph.handleAfterStabilizationHandles = append(graph.handleAfterStabilizationHandles, handle)
+	}
+	graph.handleAfterStabilization = setHandle(graph.handleAfterStabilization, handle, handlers)
 }
 
 // recompute starts the recompute cycle for the node
 // setting the recomputedAt field and possibly changing the value.
 func (graph *Graph) recompute(ctx context.Context, n INode, parallel bool) (err error) {
-	graph.numNodesRecomputed++
+	var changed bool
+	changed, err = graph.recomputeNode(ctx, n, parallel)
+	if err != nil || !changed {
+		return
+	}
+
+	nn := n.Node()
+	if parallel {
+		for _, c := range nn.children {
+			graph.recomputeHeap.addIfStaleConcurrent(c)
+		}
+	} else {
+		for _, c := range nn.children {
+			isNecessary := c.Node().isNecessary()
+			isStale := c.Node().isStale()
+			isNotInRecomputeHeap := c.Node().heightInRecomputeHeap == HeightUnset
+			if isNecessary && isStale && isNotInRecomputeHeap {
+				graph.recomputeHeap.addNodeUnsafe(c)
+			}
+		}
+	}
+	return
+}
+
+// recomputeNode recomputes a single node, returning if the node changed, but
+// does not add the node's children to the recompute heap.
+//
+// If parallel is true, the graph counters are updated atomically.
+func (graph *Graph) recomputeNode(ctx context.Context, n INode, parallel bool) (changed bool, err error) {
+	if parallel {
+		atomic.AddUint64(&graph.numNodesRecomputed, 1)
+	} else {
+		graph.numNodesRecomputed++
+	}
 
 	nn := n.Node()
 	nn.numRecomputes++
@@ -686,10 +1335,19 @@ func (graph *Graph) recompute(ctx context.Context, n INode, parallel bool) (err
 		return
 	}
 	if shouldCutoff {
+		if parallel {
+			atomic.AddUint64(&graph.numNodesCutoff, 1)
+		} else {
+			graph.numNodesCutoff++
+		}
 		return
 	}
 
-	graph.numNodesChanged++
+	if parallel {
+		atomic.AddUint64(&graph.numNodesChanged, 1)
+	} else {
+		graph.numNodesChanged++
+	}
 	nn.numChanges++
 
 	if err = nn.maybeStabilize(ctx); err != nil {
@@ -700,42 +1358,34 @@ func (graph *Graph) recompute(ctx context.Context, n INode, parallel bool) (err
 	}
 
 	nn.changedAt = graph.stabilizationNum
+	nn.changedTime = graph.stabilizationStarted.UnixNano()
+	changed = true
 	if len(nn.onUpdateHandlers) > 0 {
 		graph.handleAfterStabilizationMu.Lock()
-		graph.handleAfterStabilization[nn.id] = nn.onUpdateHandlers
+		graph.addHandleAfterStabilizationUnsafe(nn, nn.onUpdateHandlers)
 		graph.handleAfterStabilizationMu.Unlock()
 	}
 
-	if parallel {
-		graph.recomputeHeap.mu.Lock()
-		for _, c := range nn.children {
-			isNecessary := c.Node().isNecessary()
-			isStale := c.Node().isStale()
-			isNotInRecomputeHeap := c.Node().heightInRecomputeHeap == HeightUnset
-			if isNecessary && isStale && isNotInRecomputeHeap {
-				graph.recomputeHeap.addNodeUnsafe(c)
-			}
-		}
-		graph.recomputeHeap.mu.Unlock()
-	} else {
-		for _, c := range nn.children {
-			isNecessary := c.Node().isNecessary()
-			isStale := c.Node().isStale()
-			isNotInRecomputeHeap := c.Node().heightInRecomputeHeap == HeightUnset
-			if isNecessary && isStale && isNotInRecomputeHeap {
-				graph.recomputeHeap.addNodeUnsafe(c)
-			}
-		}
-	}
-
 	// recompute observers immediately because logically they're
 	// children of this node but will not have any children themselves.
 	for _, o := range nn.observers {
+		if typed, ok := o.(observerChangedAt); ok {
+			typed.setChangedAt(nn.changedAt, nn.changedTime)
+		}
 		if len(o.Node().onUpdateHandlers) > 0 {
 			graph.handleAfterStabilizationMu.Lock()
-			graph.handleAfterStabilization[nn.id] = o.Node().onUpdateHandlers
+			graph.addHandleAfterStabilizationUnsafe(o.Node(), o.Node().onUpdateHandlers)
 			graph.handleAfterStabilizationMu.Unlock()
 		}
 	}
+	if len(graph.onObserversChanged) > 0 && len(nn.observers) > 0 {
+		graph.changedObserversMu.Lock()
+		graph.changedObservers = append(graph.changedObservers, nn.observers...)
+		graph.changedObserversMu.Unlock()
+	}
+	// reports are only made by serial stabilization.
+	if graph.report != nil {
+		graph.report.ObserversChanged = append(graph.report.ObserversChanged, nn.observers...)
+	}
 	return
 }
@@ -3,6 +3,7 @@ package incr
 import (
 	"context"
 	"runtime"
+	"sync"
 	"testing"
 
 	"github.com/wcharczuk/go-incr/testutil"
@@ -23,8 +24,39 @@ func Test_New(t *testing.T) {
 func Test_New_options_MaxHeight(t *testing.T) {
 	g := New(OptGraphMaxHeight(1024))
 	testutil.NotEqual(t, 1024, DefaultMaxHeight)
-	testutil.Equal(t, 1024, len(g.recomputeHeap.heights))
-	testutil.Equal(t, 1024, len(g.adjustHeightsHeap.nodesByHeight))
+	testutil.Equal(t, 1024, g.adjustHeightsHeap.maxHeight)
+	testutil.Equal(t, 1023, g.adjustHeightsHeap.maxHeightAllowed())
+	testutil.Equal(t, DefaultMaxHeight, len(g.recomputeHeap.heights))
+	testutil.Equal(t, DefaultMaxHeight, len(g.adjustHeightsHeap.nodesByHeight))
+
+	g = New(OptGraphMaxHeight(32))
+	testutil.Equal(t, 32, len(g.recomputeHeap.heights))
+	testutil.Equal(t, 32, len(g.adjustHeightsHeap.nodesByHeight))
+}
+
+func Test_New_options_MaxHeight_default(t *testing.T) {
+	ctx := testContext()
+	g := New()
+	testutil.Equal(t, 0, g.adjustHeightsHeap.maxHeight)
+
+	const depth = 4 * DefaultMaxHeight
+	v := Var(g, 0)
+	var cursor Incr[int] = v
+	for x := 0; x < depth; x++ {
+		cursor = Map(g, cursor, func(vv int) int { return vv + 1 })
+	}
+	o, err := Observe(g, cursor)
+	testutil.NoError(t, err)
+	testutil.Equal(t, depth, cursor.Node().height)
+
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, depth, o.Value())
+
+	v.Set(1)
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, depth+1, o.Value())
 }
 
 func Test_New_options_Parallelism(t *testing.T) {
@@ -72,8 +104,7 @@ func Test_Graph_addObserver_rediscover(t *testing.T) {
 
 	v := Var(g, "hello")
 	o := MustObserve(g, v)
-	_, ok := g.observers[o.Node().ID()]
-	testutil.Equal(t, true, ok)
+	testutil.Equal(t, true, g.HasObserver(o))
 	testutil.Equal(t, 2, g.numNodes)
 	testutil.Equal(t, -1, o.Node().height)
 	testutil.Equal(t, false, g.recomputeHeap.has(o))
@@ -118,19 +149,26 @@ func Test_Graph_removeNodeFromGraph(t *testing.T) {
 	g := New()
 
 	mn00 := newMockBareNodeWithHeight(g, 2)
-	g.numNodes = 2
+	g.numNodes = 1
 
-	g.nodes[mn00.n.id] = mn00
+	g.addNode(mn00)
+	handle := mn00.n.handle
 
-	g.handleAfterStabilization[mn00.n.id] = []func(context.Context){
+	g.handleAfterStabilizationMu.Lock()
+	g.addHandleAfterStabilizationUnsafe(mn00.n, []func(context.Context){
 		func(_ context.Context) {},
 		func(_ context.Context) {},
-	}
+	})
+	g.handleAfterStabilizationMu.Unlock()
 	g.recomputeHeap.add(mn00)
 
 	g.removeNode(mn00)
 
 	testutil.Equal(t, 1, g.numNodes)
+	testutil.Equal(t, false, g.Has(mn00))
+	testutil.Nil(t, g.nodes[handle])
+	testutil.Nil(t, g.handleAfterStabilization[handle])
+	testutil.Equal(t, 0, mn00.n.handle)
 	testutil.Equal(t, false, g.recomputeHeap.has(mn00))
 	testutil.NoError(t, g.recomputeHeap.sanityCheck())
 
@@ -194,3 +232,261 @@ func Test_Graph_addChild(t *testing.T) {
 	err = g.addChild(n0, n1)
 	testutil.NoError(t, err)
 }
+
+func Test_New_options_Concurrent(t *testing.T) {
+	g := New()
+	testutil.Equal(t, false, g.concurrent)
+	g = New(OptGraphConcurrent(true))
+	testutil.Equal(t, true, g.concurrent)
+}
+
+func Test_Graph_concurrent_Stabilize_serialized(t *testing.T) {
+	ctx := testContext()
+	g := New(OptGraphConcurrent(true))
+
+	v := Var(g, 0)
+	m := Map(g, v, func(vv int) int { return vv * 2 })
+	o := MustObserve(g, m)
+
+	const workers = 8
+	const setsPerWorker = 64
+	wg := new(sync.WaitGroup)
+	errs := make(chan error, workers*setsPerWorker)
+	for x := 0; x < workers; x++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for y := 0; y < setsPerWorker; y++ {
+				v.Set(worker*setsPerWorker + y)
+				if worker%2 == 0 {
+					errs <- g.Stabilize(ctx)
+				} else {
+					errs <- g.ParallelStabilize(ctx)
+				}
+			}
+		}(x)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		testutil.NoError(t, err)
+	}
+
+	v.Set(1000)
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 2000, o.Value())
+}
+
+func Test_Graph_concurrent_Set_duringStabilization(t *testing.T) {
+	ctx := testContext()
+	g := New(OptGraphConcurrent(true))
+
+	v := Var(g, "foo")
+	entered := make(chan struct{})
+	proceed := make(chan struct{})
+	var blockOnce sync.Once
+	m := Map(g, v, func(vv string) string {
+		blockOnce.Do(func() {
+			close(entered)
+			<-proceed
+		})
+		return vv
+	})
+	o := MustObserve(g, m)
+
+	done := make(chan error)
+	go func() {
+		done <- g.Stabilize(ctx)
+	}()
+	<-entered
+
+	// the set happens while the graph is stabilizing and should be staged
+	v.Set("bar")
+	g.SetStale(m)
+	close(proceed)
+	testutil.NoError(t, <-done)
+	testutil.Equal(t, "foo", o.Value())
+	testutil.Equal(t, "bar", v.Value())
+	testutil.Equal(t, true, m.Node().heightInRecomputeHeap != HeightUnset)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "bar", o.Value())
+}
+
+func Test_Graph_concurrent_Observe_Unobserve(t *testing.T) {
+	ctx := testContext()
+	g := New(OptGraphConcurrent(true))
+
+	v := Var(g, 1)
+	maps := make([]Incr[int], 16)
+	for x := range maps {
+		maps[x] = Map(g, v, func(vv int) int { return vv + 1 })
+	}
+
+	stop := make(chan struct{})
+	stabilizeDone := make(chan error)
+	go func() {
+		defer close(stabilizeDone)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if err := g.Stabilize(ctx); err != nil {
+				stabilizeDone <- err
+				return
+			}
+		}
+	}()
+
+	wg := new(sync.WaitGroup)
+	for x := range maps {
+		wg.Add(1)
+		go func(m Incr[int]) {
+			defer wg.Done()
+			for y := 0; y < 16; y++ {
+				o := MustObserve(g, m)
+				v.Set(y)
+				o.Unobserve(ctx)
+			}
+		}(maps[x])
+	}
+	wg.Wait()
+	close(stop)
+	for err := range stabilizeDone {
+		testutil.NoError(t, err)
+	}
+
+	o := MustObserve(g, maps[0])
+	v.Set(100)
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 101, o.Value())
+	testutil.Equal(t, 1, ExpertGraph(g).NumObservers())
+}
+
+func Test_Graph_OnSetStale(t *testing.T) {
+	ctx := testContext()
+	g := New()
+
+	var seen []Identifier
+	remove := g.OnSetStale(func(n INode) {
+		seen = append(seen, n.Node().ID())
+	})
+	var other int
+	_ = g.OnSetStale(func(_ INode) {
+		other++
+	})
+
+	v0 := Var(g, "foo")
+	v1 := Var(g, "bar")
+	m := Map2(g, v0, v1, func(a, b string) string { return a + b })
+
+	v0.Set("not-necessary")
+	testutil.Empty(t, seen, "sets on unnecessary vars should not notify")
+
+	_ = MustObserve(g, m)
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+
+	v0.Set("moo")
+	g.SetStale(m)
+	testutil.Equal(t, []Identifier{v0.Node().ID(), m.Node().ID()}, seen)
+	testutil.Equal(t, 2, other)
+
+	remove()
+	remove()
+	v1.Set("baz")
+	testutil.Equal(t, 2, len(seen))
+	testutil.Equal(t, 3, other)
+}
+
+func Test_Graph_deterministic_updateHandlers(t *testing.T) {
+	stabilizers := map[string]func(*Graph, context.Context) error{
+		"Stabilize":         (*Graph).Stabilize,
+		"ParallelStabilize": (*Graph).ParallelStabilize,
+		"DataflowStabilize": (*Graph).DataflowStabilize,
+	}
+	for name, stabilize := range stabilizers {
+		stabilize := stabilize
+		t.Run(name, func(t *testing.T) {
+			ctx := testContext()
+			g := New(OptGraphDeterministic(true), OptGraphParallelism(4))
+			v := Var(g, 0)
+
+			var order []int
+			onUpdate := func(index int) func(context.Context) {
+				return func(_ context.Context) {
+					order = append(order, index)
+				}
+			}
+
+			// observe the deeper node first so that it is added to the
+			// graph first, but it should still be updated last.
+			maps := make([]Incr[int], 8)
+			for x := range maps {
+				x := x
+				maps[x] = Map(g, v, func(vv int) int {
+					runtime.Gosched()
+					return vv + x
+				})
+			}
+			last := MapN(g, func(values ...int) int { return len(values) }, maps...)
+			last.Node().OnUpdate(onUpdate(len(maps)))
+			_ = MustObserve(g, last)
+			for x, m := range maps {
+				m.Node().OnUpdate(onUpdate(x))
+				_ = MustObserve(g, m)
+			}
+
+			expected := []int{0, 1, 2, 3, 4, 5, 6, 7, 8}
+			for x := 0; x < 3; x++ {
+				order = nil
+				v.Set(v.Value() + 1)
+				err := stabilize(g, ctx)
+				testutil.NoError(t, err)
+				testutil.Equal(t, expected, order)
+			}
+		})
+	}
+}
+
+func Test_Graph_OnObserversChanged(t *testing.T) {
+	ctx := testContext()
+	g := New()
+	v0 := Var(g, "foo")
+	v1 := Var(g, "bar")
+	o0 := MustObserve(g, Map(g, v0, ident), OptObserveEqual(Equals[string]))
+	o1 := MustObserve(g, Map(g, v0, ident))
+	o2 := MustObserve(g, Map(g, v1, ident))
+
+	var changed [][]IObserver
+	g.OnObserversChanged(func(_ context.Context, observers []IObserver) {
+		changed = append(changed, observers)
+	})
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 1, len(changed))
+	testutil.Equal(t, 3, len(changed[0]))
+	testutil.Any(t, changed[0], func(o IObserver) bool { return o == o0 })
+
+	v0.Set("foo")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 2, len(changed))
+	testutil.Equal(t, []IObserver{o1}, changed[1])
+
+	v1.Set("not-bar")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 3, len(changed))
+	testutil.Equal(t, []IObserver{o2}, changed[2])
+
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 3, len(changed), "handlers should not be called if no observers changed")
+}
@@ -23,8 +23,9 @@ type Identifier [16]byte
 // the random data for the identifier in a rotating buffer, which
 // yields decent performance and uniqueness guarantees.
 //
-// If performance is still bottlenecked on creating identifiers for nodes
-// you can swap out the algorithm for generating ids with [SetIdentifierProvider].
+// Node identifiers are assigned the first time they're read with [Node.ID], but if
+// performance is still bottlenecked on creating identifiers you can swap out the
+// algorithm for generating ids with [SetIdentifierProvider].
 func NewIdentifier() (output Identifier) {
 	output = identifierProvider()
 	return
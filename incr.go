@@ -67,3 +67,9 @@ type ISentinel interface {
 	INode
 	Unwatch(context.Context)
 }
+
+// releaser is a node that holds resources while it's part of a graph, and
+// releases them when it stops being necessary or is invalidated.
+type releaser interface {
+	release()
+}
@@ -0,0 +1,231 @@
+package incrutil
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/wcharczuk/go-incr"
+)
+
+// ErrAutoStabilizerStopped is returned by [AutoStabilizerRunner.Flush] if the
+// auto stabilizer has been stopped.
+var ErrAutoStabilizerStopped = errors.New("auto stabilizer; stopped, cannot continue")
+
+// AutoStabilizerOptions are options for [AutoStabilizer].
+type AutoStabilizerOptions struct {
+	// MinInterval is the quiet period the auto stabilizer waits for after
+	// the most recent set before it stabilizes the graph.
+	//
+	// Sets that happen within the quiet period restart it, and are coalesced
+	// into a single stabilization. If zero, the graph is stabilized as soon as
+	// possible after a set, and sets that happen while the graph is stabilizing
+	// are coalesced into the next stabilization.
+	MinInterval time.Duration
+
+	// MaxDelay is the maximum time the auto stabilizer will wait after the first
+	// set of a quiet period before it stabilizes the graph, regardless of if
+	// sets are still happening.
+	//
+	// If zero, there is no maximum and a steady stream of sets spaced closer than
+	// [AutoStabilizerOptions.MinInterval] will delay stabilization indefinitely.
+	MaxDelay time.Duration
+
+	// Parallel determines if the graph is stabilized with [incr.Graph.ParallelStabilize]
+	// instead of [incr.Graph.Stabilize].
+	Parallel bool
+
+	// ErrorsBufferSize is the size of the channel returned by [AutoStabilizerRunner.Errors].
+	//
+	// Errors are dropped if the channel is full. If zero, a size of 1 is used.
+	ErrorsBufferSize int
+}
+
+// AutoStabilizer returns a new auto stabilizer that stabilizes a given graph on a
+// background goroutine when vars are set or nodes are marked stale.
+//
+// The graph must be created with [incr.OptGraphConcurrent] as sets will typically
+// happen on other goroutines than the one stabilizing the graph.
+//
+// The graph is stabilized once after the auto stabilizer starts so that any
+// changes made to the graph before the auto stabilizer was created are applied.
+//
+// The auto stabilizer runs until the given context is canceled or [AutoStabilizerRunner.Stop]
+// is called.
+func AutoStabilizer(ctx context.Context, g *incr.Graph, opts AutoStabilizerOptions) *AutoStabilizerRunner {
+	errorsBufferSize := opts.ErrorsBufferSize
+	if errorsBufferSize <= 0 {
+		errorsBufferSize = 1
+	}
+	as := &AutoStabilizerRunner{
+		graph:  g,
+		opts:   opts,
+		signal: make(chan struct{}, 1),
+		flush:  make(chan chan error),
+		errors: make(chan error, errorsBufferSize),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	as.removeOnSetStale = g.OnSetStale(func(_ incr.INode) {
+		as.notify()
+	})
+	as.notify()
+	go as.run(ctx)
+	return as
+}
+
+// AutoStabilizerRunner is a running auto stabilizer as returned by [AutoStabilizer].
+type AutoStabilizerRunner struct {
+	graph *incr.Graph
+	opts  AutoStabilizerOptions
+
+	// removeOnSetStale removes the set stale handler
+	// from the graph when the auto stabilizer stops.
+	removeOnSetStale func()
+
+	signal   chan struct{}
+	flush    chan chan error
+	errors   chan error
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// Errors returns a channel that receives errors returned by stabilization.
+//
+// The channel is closed when the auto stabilizer stops.
+func (as *AutoStabilizerRunner) Errors() <-chan error {
+	return as.errors
+}
+
+// Flush stabilizes the graph immediately, skipping any pending quiet period, and
+// waits for the stabilization to complete, returning the error from the stabilization.
+//
+// Any sets that happen before [Flush] is called will be applied when it returns.
+func (as *AutoStabilizerRunner) Flush(ctx context.Context) error {
+	reply := make(chan error, 1)
+	select {
+	case as.flush <- reply:
+	case <-as.done:
+		return ErrAutoStabilizerStopped
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err := <-reply:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stop stops the auto stabilizer, waiting for any in-progress stabilization to complete.
+//
+// Pending sets are not stabilized; call [AutoStabilizerRunner.Flush] before [Stop] to
+// apply them.
+func (as *AutoStabilizerRunner) Stop() {
+	as.stopOnce.Do(func() {
+		close(as.stop)
+	})
+	<-as.done
+}
+
+func (as *AutoStabilizerRunner) notify() {
+	select {
+	case as.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (as *AutoStabilizerRunner) run(ctx context.Context) {
+	defer close(as.done)
+	defer close(as.errors)
+	defer as.removeOnSetStale()
+	for {
+		var flushes []chan error
+		select {
+		case <-ctx.Done():
+			return
+		case <-as.stop:
+			return
+		case <-as.signal:
+			var ok bool
+			if flushes, ok = as.wait(ctx); !ok {
+				return
+			}
+		case reply := <-as.flush:
+			flushes = append(flushes, reply)
+		}
+		// the stabilization will apply any sets signaled so far.
+		select {
+		case <-as.signal:
+		default:
+		}
+		err := as.stabilize(ctx)
+		for _, reply := range flushes {
+			reply <- err
+		}
+	}
+}
+
+// wait waits for the quiet period to elapse, returning the flush request
+// that ended it early if any, and false if the auto stabilizer was stopped.
+func (as *AutoStabilizerRunner) wait(ctx context.Context) (flushes []chan error, ok bool) {
+	if as.opts.MinInterval <= 0 {
+		return nil, true
+	}
+	first := time.Now()
+	timer := time.NewTimer(as.delay(first))
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-as.stop:
+			return nil, false
+		case reply := <-as.flush:
+			return []chan error{reply}, true
+		case <-timer.C:
+			return nil, true
+		case <-as.signal:
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(as.delay(first))
+		}
+	}
+}
+
+// delay returns the quiet period remaining for a given
+// start time, clamped to the max delay if one is set.
+func (as *AutoStabilizerRunner) delay(first time.Time) time.Duration {
+	delay := as.opts.MinInterval
+	if as.opts.MaxDelay > 0 {
+		if remaining := as.opts.MaxDelay - time.Since(first); remaining < delay {
+			delay = remaining
+		}
+	}
+	if delay < 0 {
+		return 0
+	}
+	return delay
+}
+
+func (as *AutoStabilizerRunner) stabilize(ctx context.Context) (err error) {
+	if as.opts.Parallel {
+		err = as.graph.ParallelStabilize(ctx)
+	} else {
+		err = as.graph.Stabilize(ctx)
+	}
+	if err != nil {
+		select {
+		case as.errors <- err:
+		default:
+		}
+	}
+	return
+}
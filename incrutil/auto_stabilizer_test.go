@@ -0,0 +1,150 @@
+package incrutil
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wcharczuk/go-incr"
+	"github.com/wcharczuk/go-incr/testutil"
+)
+
+func Test_AutoStabilizer(t *testing.T) {
+	ctx := testContext()
+	g := incr.New(incr.OptGraphConcurrent(true))
+
+	v := incr.Var(g, "foo")
+	m := incr.Map(g, v, func(vv string) string { return vv + "-mapped" })
+	o := incr.MustObserve(g, m)
+	updates := make(chan string, 16)
+	o.OnUpdate(func(_ context.Context, value string) {
+		updates <- value
+	})
+
+	as := AutoStabilizer(ctx, g, AutoStabilizerOptions{})
+	defer as.Stop()
+
+	testutil.Equal(t, "foo-mapped", waitForUpdate(t, updates))
+
+	go v.Set("bar")
+	testutil.Equal(t, "bar-mapped", waitForUpdate(t, updates))
+}
+
+func Test_AutoStabilizer_coalesces(t *testing.T) {
+	ctx := testContext()
+	g := incr.New(incr.OptGraphConcurrent(true))
+
+	var stabilizations uint64
+	g.OnStabilizationEnd(func(_ context.Context, _ time.Time, _ error) {
+		atomic.AddUint64(&stabilizations, 1)
+	})
+	v := incr.Var(g, 0)
+	o := incr.MustObserve(g, v)
+
+	as := AutoStabilizer(ctx, g, AutoStabilizerOptions{
+		MinInterval: time.Hour,
+	})
+	defer as.Stop()
+
+	for x := 0; x < 100; x++ {
+		v.Set(x)
+	}
+	err := as.Flush(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 99, o.Value())
+	testutil.Equal(t, 1, atomic.LoadUint64(&stabilizations))
+}
+
+func Test_AutoStabilizer_maxDelay(t *testing.T) {
+	ctx := testContext()
+	g := incr.New(incr.OptGraphConcurrent(true))
+
+	v := incr.Var(g, "foo")
+	o := incr.MustObserve(g, v)
+	updates := make(chan string, 16)
+	o.OnUpdate(func(_ context.Context, value string) {
+		updates <- value
+	})
+
+	as := AutoStabilizer(ctx, g, AutoStabilizerOptions{
+		MinInterval: time.Hour,
+		MaxDelay:    time.Millisecond,
+	})
+	defer as.Stop()
+
+	v.Set("bar")
+	testutil.Equal(t, "bar", waitForUpdate(t, updates))
+}
+
+func Test_AutoStabilizer_errors(t *testing.T) {
+	ctx := testContext()
+	g := incr.New(incr.OptGraphConcurrent(true))
+
+	v := incr.Var(g, "foo")
+	m := incr.MapContext(g, v, func(_ context.Context, vv string) (string, error) {
+		if vv == "bad" {
+			return "", fmt.Errorf("this is only a test")
+		}
+		return vv, nil
+	})
+	_ = incr.MustObserve(g, m)
+
+	as := AutoStabilizer(ctx, g, AutoStabilizerOptions{
+		MinInterval: time.Hour,
+	})
+	defer as.Stop()
+
+	err := as.Flush(ctx)
+	testutil.NoError(t, err)
+
+	v.Set("bad")
+	err = as.Flush(ctx)
+	testutil.Error(t, err)
+
+	select {
+	case err = <-as.Errors():
+		testutil.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for stabilization error")
+	}
+}
+
+func Test_AutoStabilizer_Stop(t *testing.T) {
+	ctx := testContext()
+	g := incr.New(incr.OptGraphConcurrent(true))
+
+	v := incr.Var(g, "foo")
+	o := incr.MustObserve(g, v)
+
+	as := AutoStabilizer(ctx, g, AutoStabilizerOptions{})
+	err := as.Flush(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "foo", o.Value())
+
+	as.Stop()
+	as.Stop()
+
+	err = as.Flush(ctx)
+	testutil.Equal(t, ErrAutoStabilizerStopped, err)
+	_, ok := <-as.Errors()
+	testutil.Equal(t, false, ok)
+
+	select {
+	case <-as.signal:
+	default:
+	}
+	v.Set("bar")
+	testutil.Equal(t, 0, len(as.signal), "a stopped auto stabilizer should not be notified of sets")
+}
+
+func waitForUpdate[A any](t *testing.T, updates <-chan A) (value A) {
+	t.Helper()
+	select {
+	case value = <-updates:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for update")
+	}
+	return
+}
@@ -0,0 +1,204 @@
+package incrutil
+
+import (
+	"context"
+	"sync"
+
+	"github.com/wcharczuk/go-incr"
+)
+
+// BackpressurePolicy determines how channel adapters handle values
+// that arrive faster than they can be handled.
+type BackpressurePolicy int
+
+// BackpressurePolicy values.
+const (
+	// BackpressureLatestOnly keeps only the most recent value, replacing
+	// any value that has not been handled yet.
+	BackpressureLatestOnly BackpressurePolicy = iota
+	// BackpressureDropOldest buffers up to [ChanOptions.BufferSize] values,
+	// dropping the oldest buffered value when the buffer is full.
+	BackpressureDropOldest
+	// BackpressureBlock waits for each value to be handled before
+	// accepting the next value.
+	BackpressureBlock
+)
+
+// ChanOptions are options for [VarFromChan] and [ObserveChan].
+type ChanOptions struct {
+	// Policy is the backpressure policy, and defaults to [BackpressureLatestOnly].
+	Policy BackpressurePolicy
+	// BufferSize is the number of values buffered by [BackpressureDropOldest], and
+	// the size of the output channel of [ObserveChan] for [BackpressureBlock].
+	//
+	// If zero, a size of 1 is used.
+	BufferSize int
+}
+
+func (co ChanOptions) bufferSize() int {
+	if co.BufferSize <= 0 {
+		return 1
+	}
+	return co.BufferSize
+}
+
+// VarFromChan returns a var node whose value is set from the values read from a given channel.
+//
+// The backpressure policy determines how values that arrive between stabilizations are handled:
+//   - [BackpressureLatestOnly] sets the var for every value received, coalescing bursts of
+//     values such that a stabilization only sees the most recent value.
+//   - [BackpressureDropOldest] sets the var to one value per stabilization, in order, buffering
+//     up to [ChanOptions.BufferSize] values and dropping the oldest when the buffer is full.
+//   - [BackpressureBlock] sets the var to one value per stabilization, in order, and does not
+//     read the next value from the channel until the previous value has been stabilized.
+//
+// The var keeps its last value, and the channel is no longer read, when the channel is
+// closed or the context is canceled.
+//
+// Because the var is set from another goroutine, the graph should be created with
+// [incr.OptGraphConcurrent]. For the [BackpressureDropOldest] and [BackpressureBlock]
+// policies the var must be necessary (i.e. observed) for values to be stabilized.
+func VarFromChan[T any](ctx context.Context, scope incr.Scope, input <-chan T, opts ChanOptions) incr.Incr[T] {
+	var zero T
+	v := incr.Var(scope, zero)
+	stabilized := make(chan struct{}, 1)
+	v.Node().OnUpdate(func(_ context.Context) {
+		select {
+		case stabilized <- struct{}{}:
+		default:
+		}
+	})
+	go pumpChanToVar(ctx, input, v, stabilized, opts)
+	return v
+}
+
+func pumpChanToVar[T any](ctx context.Context, input <-chan T, v incr.VarIncr[T], stabilized <-chan struct{}, opts ChanOptions) {
+	var queue []T
+	var inFlight bool
+	for {
+		// block reading the input while a value is in flight.
+		readInput := input
+		if opts.Policy == BackpressureBlock && inFlight {
+			readInput = nil
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case value, ok := <-readInput:
+			if !ok {
+				return
+			}
+			switch {
+			case opts.Policy == BackpressureLatestOnly:
+				v.Set(value)
+			case !inFlight:
+				v.Set(value)
+				inFlight = true
+			default:
+				queue = append(queue, value)
+				if len(queue) > opts.bufferSize() {
+					queue = queue[1:]
+				}
+			}
+		case <-stabilized:
+			inFlight = false
+			if len(queue) > 0 {
+				v.Set(queue[0])
+				queue = queue[1:]
+				inFlight = true
+			}
+		}
+	}
+}
+
+// ObserveChan returns a channel that receives the value of an observer after each
+// stabilization where the observed value changed.
+//
+// Values are published by a change handler (see [incr.ObserveIncr.OnChange]), such that
+// if the observer was created with [incr.OptObserveEqual], values that are recomputed
+// but equal to the last published value are not published.
+//
+// The backpressure policy determines how values are handled if the channel is not read
+// fast enough:
+//   - [BackpressureLatestOnly] buffers a single value, replacing it with newer values.
+//   - [BackpressureDropOldest] buffers up to [ChanOptions.BufferSize] values, dropping the
+//     oldest buffered value when the buffer is full.
+//   - [BackpressureBlock] buffers up to [ChanOptions.BufferSize] values, and blocks the update
+//     handlers of the stabilization while the buffer is full.
+//
+// The channel is closed when the context is canceled or the observer is unobserved. Because
+// unobserving a concurrent graph waits for stabilization to complete, a blocked stabilization
+// should be released by canceling the context.
+func ObserveChan[T any](ctx context.Context, o incr.ObserveIncr[T], opts ChanOptions) <-chan T {
+	bufferSize := opts.bufferSize()
+	if opts.Policy == BackpressureLatestOnly {
+		bufferSize = 1
+	}
+	oc := &observeChan[T]{
+		ctx:        ctx,
+		output:     make(chan T, bufferSize),
+		policy:     opts.Policy,
+		unobserved: make(chan struct{}),
+	}
+	o.OnChange(oc.publish)
+	o.OnUnobserve(func(_ context.Context) {
+		close(oc.unobserved)
+	})
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-oc.unobserved:
+		}
+		oc.close()
+	}()
+	return oc.output
+}
+
+type observeChan[T any] struct {
+	ctx        context.Context
+	output     chan T
+	policy     BackpressurePolicy
+	unobserved chan struct{}
+
+	// mu interlocks publishing values with closing the output.
+	mu     sync.Mutex
+	closed bool
+}
+
+func (oc *observeChan[T]) publish(_ context.Context, _, value T) {
+	oc.mu.Lock()
+	defer oc.mu.Unlock()
+	if oc.closed {
+		return
+	}
+	if oc.policy == BackpressureBlock {
+		select {
+		case oc.output <- value:
+		case <-oc.ctx.Done():
+		case <-oc.unobserved:
+		}
+		return
+	}
+	// we are the only sender, so after dropping the
+	// oldest value there will be room for the value.
+	select {
+	case oc.output <- value:
+		return
+	default:
+	}
+	select {
+	case <-oc.output:
+	default:
+	}
+	select {
+	case oc.output <- value:
+	default:
+	}
+}
+
+func (oc *observeChan[T]) close() {
+	oc.mu.Lock()
+	defer oc.mu.Unlock()
+	oc.closed = true
+	close(oc.output)
+}
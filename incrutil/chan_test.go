@@ -0,0 +1,221 @@
+package incrutil
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wcharczuk/go-incr"
+	"github.com/wcharczuk/go-incr/testutil"
+)
+
+func Test_VarFromChan_latestOnly(t *testing.T) {
+	ctx, cancel := context.WithCancel(testContext())
+	defer cancel()
+	g := incr.New(incr.OptGraphConcurrent(true))
+	setStale := make(chan incr.INode, 16)
+	g.OnSetStale(func(n incr.INode) {
+		setStale <- n
+	})
+
+	input := make(chan int)
+	v := VarFromChan(ctx, g, input, ChanOptions{})
+	var mapCalls int
+	m := incr.Map(g, v, func(vv int) int {
+		mapCalls++
+		return vv * 10
+	})
+	om := incr.MustObserve(g, m)
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 1, mapCalls)
+
+	for x := 1; x <= 3; x++ {
+		input <- x
+		waitForUpdate(t, setStale)
+	}
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 30, om.Value())
+	testutil.Equal(t, 2, mapCalls)
+}
+
+func Test_VarFromChan_block(t *testing.T) {
+	ctx, cancel := context.WithCancel(testContext())
+	defer cancel()
+	g := incr.New(incr.OptGraphConcurrent(true))
+	setStale := make(chan incr.INode, 16)
+	g.OnSetStale(func(n incr.INode) {
+		setStale <- n
+	})
+
+	input := make(chan int)
+	v := VarFromChan(ctx, g, input, ChanOptions{Policy: BackpressureBlock})
+	// observe a map of the var so that reads don't race the var being set.
+	om := incr.MustObserve(g, incr.Map(g, v, identity[int]))
+
+	sent := make(chan int, 3)
+	go func() {
+		for x := 1; x <= 3; x++ {
+			input <- x
+			sent <- x
+		}
+	}()
+	for x := 1; x <= 3; x++ {
+		waitForUpdate(t, setStale)
+		testutil.Equal(t, x, waitForUpdate(t, sent))
+		testutil.Equal(t, 0, len(sent), "the next value should not be read until stabilization")
+		err := g.Stabilize(ctx)
+		testutil.NoError(t, err)
+		testutil.Equal(t, x, om.Value())
+	}
+}
+
+func Test_VarFromChan_dropOldest(t *testing.T) {
+	ctx, cancel := context.WithCancel(testContext())
+	defer cancel()
+	g := incr.New(incr.OptGraphConcurrent(true))
+	setStale := make(chan incr.INode, 16)
+	g.OnSetStale(func(n incr.INode) {
+		setStale <- n
+	})
+
+	input := make(chan int)
+	v := VarFromChan(ctx, g, input, ChanOptions{Policy: BackpressureDropOldest, BufferSize: 1})
+	om := incr.MustObserve(g, incr.Map(g, v, identity[int]))
+
+	for x := 1; x <= 3; x++ {
+		input <- x
+	}
+	waitForUpdate(t, setStale)
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 1, om.Value())
+
+	waitForUpdate(t, setStale)
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 3, om.Value())
+	testutil.Equal(t, 0, len(setStale))
+}
+
+func Test_ObserveChan_latestOnly(t *testing.T) {
+	ctx := testContext()
+	g := incr.New()
+	v := incr.Var(g, "foo")
+	ov := incr.MustObserve(g, v)
+	output := ObserveChan(ctx, ov, ChanOptions{})
+
+	v.Set("bar")
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	v.Set("baz")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+
+	testutil.Equal(t, "baz", <-output)
+	testutil.Equal(t, 0, len(output))
+}
+
+func Test_ObserveChan_unchanged(t *testing.T) {
+	ctx := testContext()
+	g := incr.New()
+	v := incr.Var(g, "foo")
+	var mapCalls int
+	m := incr.Map(g, v, func(vv string) string {
+		mapCalls++
+		return strings.ToUpper(vv)
+	})
+	om := incr.MustObserve(g, m, incr.OptObserveEqual(func(a, b string) bool { return a == b }))
+	output := ObserveChan(ctx, om, ChanOptions{Policy: BackpressureDropOldest, BufferSize: 4})
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "FOO", <-output)
+
+	v.Set("FOO")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 2, mapCalls)
+	testutil.Equal(t, 0, len(output), "a value that is recomputed but unchanged should not be published")
+
+	v.Set("bar")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "BAR", <-output)
+	testutil.Equal(t, 0, len(output))
+}
+
+func Test_ObserveChan_dropOldest(t *testing.T) {
+	ctx := testContext()
+	g := incr.New()
+	v := incr.Var(g, 0)
+	ov := incr.MustObserve(g, v)
+	output := ObserveChan(ctx, ov, ChanOptions{Policy: BackpressureDropOldest, BufferSize: 2})
+
+	for x := 1; x <= 3; x++ {
+		v.Set(x)
+		err := g.Stabilize(ctx)
+		testutil.NoError(t, err)
+	}
+	testutil.Equal(t, 2, <-output)
+	testutil.Equal(t, 3, <-output)
+	testutil.Equal(t, 0, len(output))
+}
+
+func Test_ObserveChan_block(t *testing.T) {
+	ctx := testContext()
+	g := incr.New()
+	v := incr.Var(g, 0)
+	ov := incr.MustObserve(g, v)
+	output := ObserveChan(ctx, ov, ChanOptions{Policy: BackpressureBlock})
+
+	done := make(chan error)
+	go func() {
+		defer close(done)
+		for x := 1; x <= 3; x++ {
+			v.Set(x)
+			if err := g.Stabilize(ctx); err != nil {
+				done <- err
+				return
+			}
+		}
+	}()
+	for x := 1; x <= 3; x++ {
+		testutil.Equal(t, x, waitForUpdate(t, output))
+	}
+	for err := range done {
+		testutil.NoError(t, err)
+	}
+}
+
+func Test_ObserveChan_closes(t *testing.T) {
+	ctx := testContext()
+	g := incr.New()
+	v := incr.Var(g, "foo")
+
+	ov0 := incr.MustObserve(g, v)
+	output0 := ObserveChan(ctx, ov0, ChanOptions{})
+	ov0.Unobserve(ctx)
+	_, ok := <-output0
+	testutil.Equal(t, false, ok)
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	ov1 := incr.MustObserve(g, v)
+	output1 := ObserveChan(cancelCtx, ov1, ChanOptions{Policy: BackpressureBlock})
+	cancel()
+	select {
+	case _, ok = <-output1:
+		testutil.Equal(t, false, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for channel to close")
+	}
+
+	// update handlers after the channel is closed should not panic.
+	v.Set("bar")
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+}
+
+func identity[A any](v A) A { return v }
@@ -3,6 +3,7 @@ package incrutil
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/wcharczuk/go-incr"
 )
@@ -27,6 +28,16 @@ type DependencyGraph[Result any] struct {
 	// with `incr.Graph::SetStale(Dependency)`, which is returned by the function `Create`.
 	CheckIfStale func(context.Context, Dependency) (bool, error)
 
+	// CheckInterval is an optional minimum time between calls to `CheckIfStale` for
+	// each dependency, which is useful if checking a dependency is expensive.
+	//
+	// If this is not provided, `CheckIfStale` is called for each dependency for each stabilization.
+	CheckInterval time.Duration
+
+	// CheckInParallel sets if the `CheckIfStale` calls that are due should be made in parallel
+	// before the dependencies are built, in which case `CheckIfStale` must be safe to call concurrently.
+	CheckInParallel bool
+
 	// Action is the function that is called to "resolve" or build a dependency.
 	//
 	// It could also be thought of as the map or stabilize function.
@@ -56,7 +67,7 @@ func (dg DependencyGraph[Result]) Create(ctx context.Context) (*incr.Graph, map[
 			dependencyLookup[d].dependedBy = append(dependencyLookup[d].dependedBy, p.Name)
 		}
 	}
-	graph := incr.New()
+	graph := incr.New(incr.OptGraphParallelSentinels(dg.CheckInParallel))
 	packageIncrementals, err := dg.createDependencyIncrLookup(graph)
 	if err != nil {
 		return nil, nil, err
@@ -111,7 +122,7 @@ func (dg DependencyGraph[Result]) createDependencyIncr(g *incr.Graph, d Dependen
 	if dg.CheckIfStale != nil {
 		_ = incr.SentinelContext(g, func(ctx context.Context) (bool, error) {
 			return dg.CheckIfStale(ctx, d)
-		}, output)
+		}, output, incr.OptSentinelInterval(dg.CheckInterval))
 	}
 	_, err := incr.Observe(g, output)
 	return output, err
@@ -3,7 +3,9 @@ package incrutil
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/wcharczuk/go-incr/testutil"
 )
@@ -71,6 +73,38 @@ func Test_DependencyGraph(t *testing.T) {
 	testutil.Equal(t, 1, actioned["pkg/util"])
 }
 
+func Test_DependencyGraph_checkInterval(t *testing.T) {
+	ctx := testContext()
+
+	var checks int32
+	dg := DependencyGraph[string]{
+		Dependencies: []Dependency{
+			{Name: "cmd/blazectl", DependsOn: []string{"pkg/util"}},
+			{Name: "pkg/util"},
+		},
+		Action: func(ctx context.Context, d Dependency) (string, error) {
+			return "ok!", nil
+		},
+		CheckIfStale: func(_ context.Context, d Dependency) (bool, error) {
+			atomic.AddInt32(&checks, 1)
+			return true, nil
+		},
+		CheckInterval:   time.Hour,
+		CheckInParallel: true,
+	}
+
+	graph, _, err := dg.Create(ctx)
+	testutil.NoError(t, err)
+
+	err = graph.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 2, atomic.LoadInt32(&checks))
+
+	err = graph.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 2, atomic.LoadInt32(&checks))
+}
+
 func Test_DependencyGraph_duplicateDependencyName(t *testing.T) {
 	ctx := testContext()
 
@@ -0,0 +1,142 @@
+package mapi
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/wcharczuk/go-incr"
+)
+
+// GroupBy returns an incremental node that groups the rows of an input map by the key returned
+// by `by` for each row, and aggregates each group with `agg`.
+//
+// Between stabilizations, rows are compared to their previous values to determine which keys changed,
+// and `agg` is only called for the groups that gained, lost or changed a row. The members map passed to
+// `agg` is owned by the node and should not be modified or retained. The node is cut off if no groups
+// are affected.
+//
+// The output map is owned by the node and should not be modified. It's copied before the affected groups
+// are aggregated, such that maps returned previously (e.g. published as observer snapshots) are never
+// modified; the copy is linear in the number of groups, but `agg` is only called for the affected groups.
+func GroupBy[K comparable, V comparable, G comparable, A any](scope incr.Scope, input incr.Incr[map[K]V], by func(V) G, agg func(G, map[K]V) A) incr.Incr[map[G]A] {
+	return incr.WithinScope(scope, &groupByIncr[K, V, G, A]{
+		n:     incr.NewNode("mapi_group_by"),
+		input: input,
+		by:    by,
+		agg:   agg,
+	})
+}
+
+var (
+	_ incr.Incr[map[string]any] = (*groupByIncr[int, int, string, any])(nil)
+	_ incr.IParents             = (*groupByIncr[int, int, string, any])(nil)
+	_ incr.ICutoff              = (*groupByIncr[int, int, string, any])(nil)
+	_ incr.IStabilize           = (*groupByIncr[int, int, string, any])(nil)
+	_ fmt.Stringer              = (*groupByIncr[int, int, string, any])(nil)
+)
+
+type groupByIncr[K comparable, V comparable, G comparable, A any] struct {
+	n     *incr.Node
+	input incr.Incr[map[K]V]
+	by    func(V) G
+	agg   func(G, map[K]V) A
+
+	last map[K]V
+	// inputChangedAt is the stabilization number
+	// the input had changed at as of the last diff.
+	inputChangedAt uint64
+	groups         map[G]map[K]V
+	// dirty holds the groups that need to be aggregated.
+	dirty map[G]struct{}
+	val   map[G]A
+}
+
+func (gb *groupByIncr[K, V, G, A]) Parents() []incr.INode {
+	return []incr.INode{gb.input}
+}
+
+func (gb *groupByIncr[K, V, G, A]) Node() *incr.Node { return gb.n }
+
+func (gb *groupByIncr[K, V, G, A]) Value() map[G]A { return gb.val }
+
+// Cutoff diffs the input, and cuts off the recomputation
+// if no groups are affected by the changes.
+func (gb *groupByIncr[K, V, G, A]) Cutoff(_ context.Context) (bool, error) {
+	gb.diff()
+	return len(gb.dirty) == 0 && gb.val != nil, nil
+}
+
+// diff compares the rows of the input to their previous rows if the input
+// changed since the last diff, and collects the groups that are affected.
+//
+// Because the input is a whole map, diffing it is linear in its size.
+func (gb *groupByIncr[K, V, G, A]) diff() {
+	if gb.groups == nil {
+		gb.last = make(map[K]V)
+		gb.groups = make(map[G]map[K]V)
+		gb.dirty = make(map[G]struct{})
+	}
+	changedAt := incr.ExpertNode(gb.input).ChangedAt()
+	if changedAt == gb.inputChangedAt && gb.val != nil {
+		return
+	}
+	gb.inputChangedAt = changedAt
+
+	input := gb.input.Value()
+	for k, v := range input {
+		oldV, hadK := gb.last[k]
+		if hadK && oldV == v {
+			continue
+		}
+		if hadK {
+			oldG := gb.by(oldV)
+			delete(gb.groups[oldG], k)
+			gb.dirty[oldG] = struct{}{}
+		}
+		g := gb.by(v)
+		members, ok := gb.groups[g]
+		if !ok {
+			members = make(map[K]V)
+			gb.groups[g] = members
+		}
+		members[k] = v
+		gb.last[k] = v
+		gb.dirty[g] = struct{}{}
+	}
+	// every current row is now in the previous rows, so rows
+	// were only removed if there are more previous rows.
+	if len(gb.last) == len(input) {
+		return
+	}
+	for k, oldV := range gb.last {
+		if _, ok := input[k]; !ok {
+			oldG := gb.by(oldV)
+			delete(gb.groups[oldG], k)
+			delete(gb.last, k)
+			gb.dirty[oldG] = struct{}{}
+		}
+	}
+}
+
+// Stabilize aggregates the affected groups into a copy of the output.
+func (gb *groupByIncr[K, V, G, A]) Stabilize(_ context.Context) error {
+	val := maps.Clone(gb.val)
+	if val == nil {
+		val = make(map[G]A)
+	}
+	for g := range gb.dirty {
+		members := gb.groups[g]
+		if len(members) == 0 {
+			delete(gb.groups, g)
+			delete(val, g)
+			continue
+		}
+		val[g] = gb.agg(g, members)
+	}
+	gb.val = val
+	clear(gb.dirty)
+	return nil
+}
+
+func (gb *groupByIncr[K, V, G, A]) String() string { return gb.n.String() }
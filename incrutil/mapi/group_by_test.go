@@ -0,0 +1,179 @@
+package mapi
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/wcharczuk/go-incr"
+	"github.com/wcharczuk/go-incr/testutil"
+)
+
+func sumQuantity(_ string, members map[int]testOrder) (total int) {
+	for _, o := range members {
+		total += o.Quantity
+	}
+	return
+}
+
+func Test_GroupBy(t *testing.T) {
+	ctx := context.Background()
+	g := incr.New()
+	orders := incr.Var(g, map[int]testOrder{
+		1: {"AAPL", 10},
+		2: {"MSFT", 5},
+		3: {"AAPL", 1},
+	})
+
+	var calls []string
+	gb := GroupBy(g, orders, func(o testOrder) string {
+		return o.Symbol
+	}, func(symbol string, members map[int]testOrder) int {
+		calls = append(calls, symbol)
+		return sumQuantity(symbol, members)
+	})
+	ogb := incr.MustObserve(g, gb)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, map[string]int{"AAPL": 11, "MSFT": 5}, ogb.Value())
+	testutil.Equal(t, 2, len(calls))
+
+	calls = nil
+	orders.Set(map[int]testOrder{
+		1: {"AAPL", 10},
+		2: {"MSFT", 7},
+		3: {"AAPL", 1},
+	})
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, map[string]int{"AAPL": 11, "MSFT": 7}, ogb.Value())
+	testutil.Equal(t, []string{"MSFT"}, calls, "only the changed group should be aggregated")
+
+	calls = nil
+	orders.Set(map[int]testOrder{
+		1: {"AAPL", 10},
+		3: {"AAPL", 1},
+	})
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, map[string]int{"AAPL": 11}, ogb.Value())
+	testutil.Empty(t, calls)
+}
+
+func Test_GroupBy_cutoff(t *testing.T) {
+	ctx := context.Background()
+	g := incr.New()
+	orders := incr.Var(g, map[int]testOrder{
+		1: {"AAPL", 10},
+	})
+	gb := GroupBy(g, orders, func(o testOrder) string {
+		return o.Symbol
+	}, sumQuantity)
+	var childCalls int
+	m := incr.Map(g, gb, func(v map[string]int) int {
+		childCalls++
+		return len(v)
+	})
+	om := incr.MustObserve(g, m)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 1, om.Value())
+	testutil.Equal(t, 1, childCalls)
+
+	orders.Set(map[int]testOrder{
+		1: {"AAPL", 10},
+	})
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 1, childCalls, "unchanged rows should not change the group by")
+
+	orders.Set(map[int]testOrder{
+		1: {"AAPL", 10},
+		2: {"MSFT", 1},
+	})
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 2, om.Value())
+	testutil.Equal(t, 2, childCalls)
+}
+
+func Test_GroupBy_snapshot(t *testing.T) {
+	ctx := context.Background()
+	g := incr.New(incr.OptGraphObserverSnapshots(true))
+	orders := incr.Var(g, map[int]testOrder{
+		1: {"AAPL", 10},
+		2: {"MSFT", 5},
+	})
+	gb := GroupBy(g, orders, func(o testOrder) string {
+		return o.Symbol
+	}, sumQuantity)
+	ogb := incr.MustObserve(g, gb)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	first, _ := ogb.Snapshot()
+	testutil.Equal(t, map[string]int{"AAPL": 10, "MSFT": 5}, first)
+
+	// read the snapshots while the graph stabilizes.
+	done := make(chan struct{})
+	read := make(chan int)
+	go func() {
+		var reads int
+		defer func() { read <- reads }()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			snapshot, _ := ogb.Snapshot()
+			for range snapshot {
+				reads++
+			}
+		}
+	}()
+	for x := 1; x <= 32; x++ {
+		orders.Set(map[int]testOrder{
+			1: {"AAPL", 10 + x},
+			2: {"MSFT", 5},
+			3: {"GOOG", x},
+		})
+		err = g.Stabilize(ctx)
+		testutil.NoError(t, err)
+	}
+	close(done)
+	<-read
+
+	testutil.Equal(t, map[string]int{"AAPL": 10, "MSFT": 5}, first, "a published snapshot should not be modified")
+	last, _ := ogb.Snapshot()
+	testutil.Equal(t, map[string]int{"AAPL": 42, "MSFT": 5, "GOOG": 32}, last)
+}
+
+func Test_GroupBy_naive(t *testing.T) {
+	ctx := context.Background()
+	r := rand.New(rand.NewSource(5678))
+
+	g := incr.New()
+	orders := incr.Var(g, map[int]testOrder{})
+	gb := GroupBy(g, orders, func(o testOrder) string {
+		return o.Symbol
+	}, sumQuantity)
+	ogb := incr.MustObserve(g, gb)
+
+	currentOrders := make(map[int]testOrder)
+	for step := 0; step < 256; step++ {
+		currentOrders = randomlyMutateOrders(r, currentOrders)
+		orders.Set(currentOrders)
+		err := g.Stabilize(ctx)
+		testutil.NoError(t, err)
+
+		expected := make(map[string]int)
+		for _, o := range currentOrders {
+			expected[o.Symbol] += o.Quantity
+		}
+		testutil.Equal(t, expected, ogb.Value(), fmt.Sprintf("group by step %d", step))
+	}
+}
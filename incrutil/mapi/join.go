@@ -0,0 +1,220 @@
+package mapi
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/wcharczuk/go-incr"
+)
+
+// InnerJoin returns an incremental node that joins the rows of a left map with the rows of a right map,
+// matching each left row to a right row by the key returned by `on` for the left row.
+//
+// The output is keyed by the left row keys, and only left rows with a matching right row are included.
+//
+// Between stabilizations, rows are compared to their previous values to determine which keys changed
+// on either side, and `fn` is only called for the left rows affected by those changes. A change to a
+// right row only re-joins the left rows that reference it, and the node is cut off if no rows are affected.
+//
+// The output map is owned by the node and should not be modified. It's copied before the affected rows
+// are re-joined, such that maps returned previously (e.g. published as observer snapshots) are never
+// modified; the copy is linear in the size of the output, but `fn` is only called for the affected rows.
+func InnerJoin[LK, RK comparable, L, R comparable, O any](scope incr.Scope, left incr.Incr[map[LK]L], right incr.Incr[map[RK]R], on func(L) RK, fn func(L, R) O) incr.Incr[map[LK]O] {
+	return incr.WithinScope(scope, &joinIncr[LK, RK, L, R, O]{
+		n:     incr.NewNode("mapi_inner_join"),
+		left:  left,
+		right: right,
+		on:    on,
+		fn: func(lv L, rv R, _ bool) O {
+			return fn(lv, rv)
+		},
+	})
+}
+
+// LeftJoin returns an incremental node that joins the rows of a left map with the rows of a right map,
+// matching each left row to a right row by the key returned by `on` for the left row.
+//
+// The output is keyed by the left row keys, and every left row is included; `fn` is passed the
+// zero value for the right row and false if there is no matching right row.
+//
+// Between stabilizations, rows are compared to their previous values to determine which keys changed
+// on either side, and `fn` is only called for the left rows affected by those changes. A change to a
+// right row only re-joins the left rows that reference it, and the node is cut off if no rows are affected.
+//
+// The output map is owned by the node and should not be modified. It's copied before the affected rows
+// are re-joined, such that maps returned previously (e.g. published as observer snapshots) are never
+// modified; the copy is linear in the size of the output, but `fn` is only called for the affected rows.
+func LeftJoin[LK, RK comparable, L, R comparable, O any](scope incr.Scope, left incr.Incr[map[LK]L], right incr.Incr[map[RK]R], on func(L) RK, fn func(L, R, bool) O) incr.Incr[map[LK]O] {
+	return incr.WithinScope(scope, &joinIncr[LK, RK, L, R, O]{
+		n:     incr.NewNode("mapi_left_join"),
+		left:  left,
+		right: right,
+		on:    on,
+		fn:    fn,
+		outer: true,
+	})
+}
+
+var (
+	_ incr.Incr[map[string]any] = (*joinIncr[string, string, int, int, any])(nil)
+	_ incr.IParents             = (*joinIncr[string, string, int, int, any])(nil)
+	_ incr.ICutoff              = (*joinIncr[string, string, int, int, any])(nil)
+	_ incr.IStabilize           = (*joinIncr[string, string, int, int, any])(nil)
+	_ fmt.Stringer              = (*joinIncr[string, string, int, int, any])(nil)
+)
+
+type joinIncr[LK, RK comparable, L, R comparable, O any] struct {
+	n     *incr.Node
+	left  incr.Incr[map[LK]L]
+	right incr.Incr[map[RK]R]
+	on    func(L) RK
+	fn    func(L, R, bool) O
+	outer bool
+
+	lastLeft  map[LK]L
+	lastRight map[RK]R
+	// leftChangedAt and rightChangedAt are the stabilization
+	// numbers the inputs had changed at as of the last diff.
+	leftChangedAt  uint64
+	rightChangedAt uint64
+	// index holds the left keys that reference a given right key.
+	index map[RK]map[LK]struct{}
+	// dirty holds the left keys whose output rows need to be re-joined.
+	dirty map[LK]struct{}
+	val   map[LK]O
+}
+
+func (j *joinIncr[LK, RK, L, R, O]) Parents() []incr.INode {
+	return []incr.INode{j.left, j.right}
+}
+
+func (j *joinIncr[LK, RK, L, R, O]) Node() *incr.Node { return j.n }
+
+func (j *joinIncr[LK, RK, L, R, O]) Value() map[LK]O { return j.val }
+
+// Cutoff diffs the inputs, and cuts off the recomputation
+// if no output rows are affected by the changes.
+func (j *joinIncr[LK, RK, L, R, O]) Cutoff(_ context.Context) (bool, error) {
+	j.diff()
+	return len(j.dirty) == 0 && j.val != nil, nil
+}
+
+// diff compares the rows of the inputs that changed since the last diff to
+// their previous rows, and collects the left keys whose output rows are affected.
+//
+// Because the inputs are whole maps, diffing an input is linear in its size,
+// but an input that has not changed is not scanned.
+func (j *joinIncr[LK, RK, L, R, O]) diff() {
+	if j.index == nil {
+		j.lastLeft = make(map[LK]L)
+		j.lastRight = make(map[RK]R)
+		j.index = make(map[RK]map[LK]struct{})
+		j.dirty = make(map[LK]struct{})
+	}
+	if changedAt := incr.ExpertNode(j.left).ChangedAt(); changedAt != j.leftChangedAt || j.val == nil {
+		j.leftChangedAt = changedAt
+		j.diffLeft(j.left.Value())
+	}
+	if changedAt := incr.ExpertNode(j.right).ChangedAt(); changedAt != j.rightChangedAt || j.val == nil {
+		j.rightChangedAt = changedAt
+		j.diffRight(j.right.Value())
+	}
+}
+
+func (j *joinIncr[LK, RK, L, R, O]) diffLeft(left map[LK]L) {
+	for lk, lv := range left {
+		oldLv, hadLk := j.lastLeft[lk]
+		if hadLk && oldLv == lv {
+			continue
+		}
+		if hadLk {
+			j.unindex(j.on(oldLv), lk)
+		}
+		j.addIndex(j.on(lv), lk)
+		j.lastLeft[lk] = lv
+		j.dirty[lk] = struct{}{}
+	}
+	// every current row is now in the previous rows, so rows
+	// were only removed if there are more previous rows.
+	if len(j.lastLeft) == len(left) {
+		return
+	}
+	for lk, oldLv := range j.lastLeft {
+		if _, ok := left[lk]; !ok {
+			j.unindex(j.on(oldLv), lk)
+			delete(j.lastLeft, lk)
+			j.dirty[lk] = struct{}{}
+		}
+	}
+}
+
+func (j *joinIncr[LK, RK, L, R, O]) diffRight(right map[RK]R) {
+	for rk, rv := range right {
+		if oldRv, hadRk := j.lastRight[rk]; hadRk && oldRv == rv {
+			continue
+		}
+		j.lastRight[rk] = rv
+		for lk := range j.index[rk] {
+			j.dirty[lk] = struct{}{}
+		}
+	}
+	if len(j.lastRight) == len(right) {
+		return
+	}
+	for rk := range j.lastRight {
+		if _, ok := right[rk]; !ok {
+			delete(j.lastRight, rk)
+			for lk := range j.index[rk] {
+				j.dirty[lk] = struct{}{}
+			}
+		}
+	}
+}
+
+// Stabilize re-joins the affected left rows into a copy of the output.
+func (j *joinIncr[LK, RK, L, R, O]) Stabilize(_ context.Context) error {
+	left, right := j.left.Value(), j.right.Value()
+	val := maps.Clone(j.val)
+	if val == nil {
+		val = make(map[LK]O, len(left))
+	}
+	for lk := range j.dirty {
+		lv, ok := left[lk]
+		if !ok {
+			delete(val, lk)
+			continue
+		}
+		rv, found := right[j.on(lv)]
+		if !found && !j.outer {
+			delete(val, lk)
+			continue
+		}
+		val[lk] = j.fn(lv, rv, found)
+	}
+	j.val = val
+	clear(j.dirty)
+	return nil
+}
+
+func (j *joinIncr[LK, RK, L, R, O]) addIndex(rk RK, lk LK) {
+	keys, ok := j.index[rk]
+	if !ok {
+		keys = make(map[LK]struct{})
+		j.index[rk] = keys
+	}
+	keys[lk] = struct{}{}
+}
+
+func (j *joinIncr[LK, RK, L, R, O]) unindex(rk RK, lk LK) {
+	keys, ok := j.index[rk]
+	if !ok {
+		return
+	}
+	delete(keys, lk)
+	if len(keys) == 0 {
+		delete(j.index, rk)
+	}
+}
+
+func (j *joinIncr[LK, RK, L, R, O]) String() string { return j.n.String() }
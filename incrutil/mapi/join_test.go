@@ -0,0 +1,312 @@
+package mapi
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/wcharczuk/go-incr"
+	"github.com/wcharczuk/go-incr/testutil"
+)
+
+type testOrder struct {
+	Symbol   string
+	Quantity int
+}
+
+type testSymbol struct {
+	Name  string
+	Price int
+}
+
+type testOrderValue struct {
+	Symbol string
+	Value  int
+	Found  bool
+}
+
+func Test_InnerJoin(t *testing.T) {
+	ctx := context.Background()
+	g := incr.New()
+	orders := incr.Var(g, map[int]testOrder{
+		1: {"AAPL", 10},
+		2: {"MSFT", 5},
+		3: {"GOOG", 1},
+	})
+	symbols := incr.Var(g, map[string]testSymbol{
+		"AAPL": {"Apple", 100},
+		"MSFT": {"Microsoft", 200},
+	})
+
+	var calls int
+	j := InnerJoin(g, orders, symbols, func(o testOrder) string {
+		return o.Symbol
+	}, func(o testOrder, s testSymbol) int {
+		calls++
+		return o.Quantity * s.Price
+	})
+	oj := incr.MustObserve(g, j)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, map[int]int{1: 1000, 2: 1000}, oj.Value())
+	testutil.Equal(t, 2, calls)
+
+	symbols.Set(map[string]testSymbol{
+		"AAPL": {"Apple", 100},
+		"MSFT": {"Microsoft", 300},
+		"GOOG": {"Alphabet", 50},
+	})
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, map[int]int{1: 1000, 2: 1500, 3: 50}, oj.Value())
+	testutil.Equal(t, 4, calls, "only the orders for changed symbols should be re-joined")
+
+	orders.Set(map[int]testOrder{
+		1: {"AAPL", 10},
+		2: {"MSFT", 5},
+	})
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, map[int]int{1: 1000, 2: 1500}, oj.Value())
+	testutil.Equal(t, 4, calls)
+
+	symbols.Set(map[string]testSymbol{
+		"MSFT": {"Microsoft", 300},
+		"GOOG": {"Alphabet", 50},
+	})
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, map[int]int{2: 1500}, oj.Value())
+	testutil.Equal(t, 4, calls)
+}
+
+func Test_LeftJoin(t *testing.T) {
+	ctx := context.Background()
+	g := incr.New()
+	orders := incr.Var(g, map[int]testOrder{
+		1: {"AAPL", 10},
+		2: {"GOOG", 1},
+	})
+	symbols := incr.Var(g, map[string]testSymbol{
+		"AAPL": {"Apple", 100},
+	})
+
+	j := LeftJoin(g, orders, symbols, func(o testOrder) string {
+		return o.Symbol
+	}, func(o testOrder, s testSymbol, found bool) testOrderValue {
+		return testOrderValue{Symbol: o.Symbol, Value: o.Quantity * s.Price, Found: found}
+	})
+	oj := incr.MustObserve(g, j)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, map[int]testOrderValue{
+		1: {"AAPL", 1000, true},
+		2: {"GOOG", 0, false},
+	}, oj.Value())
+
+	symbols.Set(map[string]testSymbol{
+		"AAPL": {"Apple", 100},
+		"GOOG": {"Alphabet", 50},
+	})
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, map[int]testOrderValue{
+		1: {"AAPL", 1000, true},
+		2: {"GOOG", 50, true},
+	}, oj.Value())
+
+	orders.Set(map[int]testOrder{
+		1: {"MSFT", 10},
+		2: {"GOOG", 1},
+	})
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, map[int]testOrderValue{
+		1: {"MSFT", 0, false},
+		2: {"GOOG", 50, true},
+	}, oj.Value())
+}
+
+func Test_InnerJoin_cutoff(t *testing.T) {
+	ctx := context.Background()
+	g := incr.New()
+	orders := incr.Var(g, map[int]testOrder{
+		1: {"AAPL", 10},
+	})
+	symbols := incr.Var(g, map[string]testSymbol{
+		"AAPL": {"Apple", 100},
+	})
+
+	j := InnerJoin(g, orders, symbols, func(o testOrder) string {
+		return o.Symbol
+	}, func(o testOrder, s testSymbol) int {
+		return o.Quantity * s.Price
+	})
+	var childCalls int
+	m := incr.Map(g, j, func(v map[int]int) int {
+		childCalls++
+		return len(v)
+	})
+	om := incr.MustObserve(g, m)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 1, om.Value())
+	testutil.Equal(t, 1, childCalls)
+
+	symbols.Set(map[string]testSymbol{
+		"AAPL": {"Apple", 100},
+		"MSFT": {"Microsoft", 200},
+	})
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 1, childCalls, "a symbol no order references should not change the join")
+
+	symbols.Set(map[string]testSymbol{
+		"AAPL": {"Apple", 200},
+	})
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 2, childCalls)
+}
+
+func Test_InnerJoin_snapshot(t *testing.T) {
+	ctx := context.Background()
+	g := incr.New(incr.OptGraphObserverSnapshots(true))
+	orders := incr.Var(g, map[int]testOrder{
+		1: {"AAPL", 10},
+		2: {"MSFT", 5},
+	})
+	symbols := incr.Var(g, map[string]testSymbol{
+		"AAPL": {"Apple", 100},
+		"MSFT": {"Microsoft", 200},
+	})
+	j := InnerJoin(g, orders, symbols, func(o testOrder) string {
+		return o.Symbol
+	}, func(o testOrder, s testSymbol) int {
+		return o.Quantity * s.Price
+	})
+	oj := incr.MustObserve(g, j)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	first, _ := oj.Snapshot()
+	testutil.Equal(t, map[int]int{1: 1000, 2: 1000}, first)
+
+	// read the snapshots while the graph stabilizes.
+	done := make(chan struct{})
+	read := make(chan int)
+	go func() {
+		var reads int
+		defer func() { read <- reads }()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			snapshot, _ := oj.Snapshot()
+			for range snapshot {
+				reads++
+			}
+		}
+	}()
+	for x := 1; x <= 32; x++ {
+		orders.Set(map[int]testOrder{
+			1: {"AAPL", 10 + x},
+			2: {"MSFT", 5},
+			3: {"AAPL", x},
+		})
+		err = g.Stabilize(ctx)
+		testutil.NoError(t, err)
+	}
+	close(done)
+	<-read
+
+	testutil.Equal(t, map[int]int{1: 1000, 2: 1000}, first, "a published snapshot should not be modified")
+	last, _ := oj.Snapshot()
+	testutil.Equal(t, map[int]int{1: 4200, 2: 1000, 3: 3200}, last)
+}
+
+func Test_InnerJoin_LeftJoin_naive(t *testing.T) {
+	ctx := context.Background()
+	r := rand.New(rand.NewSource(1234))
+
+	g := incr.New()
+	orders := incr.Var(g, map[int]testOrder{})
+	symbols := incr.Var(g, map[string]testSymbol{})
+	on := func(o testOrder) string { return o.Symbol }
+	inner := InnerJoin(g, orders, symbols, on, func(o testOrder, s testSymbol) int {
+		return o.Quantity * s.Price
+	})
+	left := LeftJoin(g, orders, symbols, on, func(o testOrder, s testSymbol, found bool) testOrderValue {
+		return testOrderValue{Symbol: o.Symbol, Value: o.Quantity * s.Price, Found: found}
+	})
+	oi := incr.MustObserve(g, inner)
+	ol := incr.MustObserve(g, left)
+
+	currentOrders := make(map[int]testOrder)
+	currentSymbols := make(map[string]testSymbol)
+	for step := 0; step < 256; step++ {
+		currentOrders = randomlyMutateOrders(r, currentOrders)
+		if r.Intn(2) == 0 {
+			currentSymbols = randomlyMutateSymbols(r, currentSymbols)
+		}
+		orders.Set(currentOrders)
+		symbols.Set(currentSymbols)
+		err := g.Stabilize(ctx)
+		testutil.NoError(t, err)
+
+		expectedInner := make(map[int]int)
+		expectedLeft := make(map[int]testOrderValue)
+		for k, o := range currentOrders {
+			s, found := currentSymbols[o.Symbol]
+			if found {
+				expectedInner[k] = o.Quantity * s.Price
+			}
+			expectedLeft[k] = testOrderValue{Symbol: o.Symbol, Value: o.Quantity * s.Price, Found: found}
+		}
+		testutil.Equal(t, expectedInner, oi.Value(), fmt.Sprintf("inner join step %d", step))
+		testutil.Equal(t, expectedLeft, ol.Value(), fmt.Sprintf("left join step %d", step))
+	}
+}
+
+var testSymbolNames = []string{"AAPL", "MSFT", "GOOG", "AMZN", "NVDA", "TSLA"}
+
+func randomlyMutateOrders(r *rand.Rand, orders map[int]testOrder) map[int]testOrder {
+	output := make(map[int]testOrder, len(orders))
+	for k, v := range orders {
+		output[k] = v
+	}
+	for x := 0; x < 1+r.Intn(4); x++ {
+		key := r.Intn(32)
+		if r.Intn(4) == 0 {
+			delete(output, key)
+			continue
+		}
+		output[key] = testOrder{
+			Symbol:   testSymbolNames[r.Intn(len(testSymbolNames))],
+			Quantity: 1 + r.Intn(10),
+		}
+	}
+	return output
+}
+
+func randomlyMutateSymbols(r *rand.Rand, symbols map[string]testSymbol) map[string]testSymbol {
+	output := make(map[string]testSymbol, len(symbols))
+	for k, v := range symbols {
+		output[k] = v
+	}
+	for x := 0; x < 1+r.Intn(2); x++ {
+		key := testSymbolNames[r.Intn(len(testSymbolNames))]
+		if r.Intn(4) == 0 {
+			delete(output, key)
+			continue
+		}
+		output[key] = testSymbol{Name: key, Price: 1 + r.Intn(100)}
+	}
+	return output
+}
@@ -0,0 +1,145 @@
+package incr
+
+import (
+	"context"
+	"fmt"
+)
+
+// Join returns an incremental that flattens an incremental of incrementals,
+// yielding the value of whichever incremental the input currently holds.
+//
+// It is equivalent to a [Bind] whose function returns its input, but does not
+// create a scope; the inner incrementals are created elsewhere, and are linked
+// to and unlinked from the join as the input changes.
+func Join[A any](scope Scope, input Incr[Incr[A]]) Incr[A] {
+	join := &join[A]{
+		graph: scope.scopeGraph(),
+		lhs:   input,
+	}
+	joinLeftChange := WithinScope(scope, &joinLeftChangeIncr[A]{
+		n:       NewNode("join-lhs-change"),
+		join:    join,
+		parents: []INode{input},
+	})
+	join.lhsChange = joinLeftChange
+	joinMain := WithinScope(scope, &joinMainIncr[A]{
+		n:       NewNode("join"),
+		join:    join,
+		parents: []INode{joinLeftChange},
+	})
+	join.main = joinMain
+	return joinMain
+}
+
+var (
+	_ Incr[bool]           = (*joinMainIncr[bool])(nil)
+	_ IStabilize           = (*joinMainIncr[bool])(nil)
+	_ IStale               = (*joinMainIncr[bool])(nil)
+	_ IShouldBeInvalidated = (*joinMainIncr[bool])(nil)
+	_ fmt.Stringer         = (*joinMainIncr[bool])(nil)
+
+	_ INode                = (*joinLeftChangeIncr[bool])(nil)
+	_ IStabilize           = (*joinLeftChangeIncr[bool])(nil)
+	_ IShouldBeInvalidated = (*joinLeftChangeIncr[bool])(nil)
+	_ IBindChange          = (*joinLeftChangeIncr[bool])(nil)
+	_ fmt.Stringer         = (*joinLeftChangeIncr[bool])(nil)
+)
+
+// join is a root struct that holds shared
+// information for both the main and the lhs-change.
+type join[A any] struct {
+	graph     *Graph
+	lhs       Incr[Incr[A]]
+	rhs       Incr[A]
+	main      *joinMainIncr[A]
+	lhsChange *joinLeftChangeIncr[A]
+}
+
+type joinMainIncr[A any] struct {
+	n       *Node
+	join    *join[A]
+	value   A
+	parents []INode
+}
+
+func (j *joinMainIncr[A]) Parents() []INode {
+	return j.parents
+}
+
+func (j *joinMainIncr[A]) Stale() bool {
+	return j.n.recomputedAt == 0 || j.n.isStaleInRespectToParent()
+}
+
+func (j *joinMainIncr[A]) ShouldBeInvalidated() bool {
+	return !j.join.lhsChange.Node().valid
+}
+
+func (j *joinMainIncr[A]) Node() *Node { return j.n }
+
+func (j *joinMainIncr[A]) Value() A {
+	j.n.checkRead(j)
+	return j.value
+}
+
+func (j *joinMainIncr[A]) Stabilize(_ context.Context) error {
+	if j.join.rhs != nil {
+		j.value = j.join.rhs.Value()
+	} else {
+		var zero A
+		j.value = zero
+	}
+	return nil
+}
+
+func (j *joinMainIncr[A]) String() string {
+	return j.n.String()
+}
+
+type joinLeftChangeIncr[A any] struct {
+	n       *Node
+	join    *join[A]
+	parents []INode
+}
+
+func (j *joinLeftChangeIncr[A]) Parents() []INode {
+	return j.parents
+}
+
+func (j *joinLeftChangeIncr[A]) Node() *Node { return j.n }
+
+func (j *joinLeftChangeIncr[A]) ShouldBeInvalidated() bool {
+	return !j.join.lhs.Node().valid
+}
+
+// RightScopeNodes returns nil as a join does not create a scope.
+func (j *joinLeftChangeIncr[A]) RightScopeNodes() []INode {
+	return nil
+}
+
+func (j *joinLeftChangeIncr[A]) Stabilize(_ context.Context) error {
+	oldRhs, oldParents := j.join.rhs, j.join.main.parents
+	j.join.rhs = j.join.lhs.Value()
+	if j.join.rhs != nil {
+		j.join.main.parents = []INode{j, j.join.rhs}
+	} else {
+		j.join.main.parents = []INode{j}
+	}
+	if oldRhs == nil && j.join.rhs == nil {
+		return nil
+	}
+	graph := GraphForNode(j)
+	if err := graph.changeParent(j.join.main, oldRhs, j.join.rhs); err != nil {
+		// keep the previous inner incremental linked such that the join is unchanged.
+		newRhs := j.join.rhs
+		j.join.rhs, j.join.main.parents = oldRhs, oldParents
+		graph.unchangeParent(j.join.main, oldRhs, newRhs)
+		graph.propagateInvalidity()
+		return err
+	}
+	graph.propagateInvalidity()
+	return nil
+}
+
+func (j *joinLeftChangeIncr[A]) String() string {
+	return j.n.String()
+}
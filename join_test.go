@@ -0,0 +1,174 @@
+package incr
+
+import (
+	"testing"
+
+	"github.com/wcharczuk/go-incr/testutil"
+)
+
+func Test_Join(t *testing.T) {
+	ctx := testContext()
+	g := New()
+
+	a := Var(g, "a")
+	b := Map(g, Map(g, Var(g, "b"), ident), ident)
+	which := Var[Incr[string]](g, a)
+	j := Join(g, which)
+	o := MustObserve(g, j)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "a", o.Value())
+	testutil.Equal(t, false, g.Has(b))
+
+	which.Set(b)
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "b", o.Value())
+	testutil.Equal(t, true, g.Has(b))
+	testutil.Equal(t, false, g.Has(a), "the previous inner incremental should be unlinked")
+	testutil.Equal(t, true, j.Node().height > b.Node().height)
+
+	a.Set("not-a")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "b", o.Value())
+
+	which.Set(a)
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "not-a", o.Value())
+
+	a.Set("a")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "a", o.Value())
+}
+
+func Test_Join_nil(t *testing.T) {
+	ctx := testContext()
+	g := New()
+
+	a := Var(g, "a")
+	which := Var[Incr[string]](g, nil)
+	o := MustObserve(g, Join(g, which))
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "", o.Value())
+
+	which.Set(a)
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "a", o.Value())
+
+	which.Set(nil)
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "", o.Value())
+	testutil.Equal(t, false, g.Has(a))
+}
+
+func Test_Join_withinBind(t *testing.T) {
+	ctx := testContext()
+	g := New()
+
+	inner := Var(g, "inner")
+	outer := Var(g, "a")
+	b := Bind(g, outer, func(bs Scope, which string) Incr[string] {
+		return Join(bs, Return[Incr[string]](bs, Map(bs, inner, func(vv string) string {
+			return which + "-" + vv
+		})))
+	})
+	o := MustObserve(g, b)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "a-inner", o.Value())
+
+	outer.Set("b")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "b-inner", o.Value())
+
+	inner.Set("not-inner")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "b-not-inner", o.Value())
+}
+
+func Test_Join_changeParentError(t *testing.T) {
+	ctx := testContext()
+	g := New()
+
+	a := Var(g, "a")
+	b := Var(g, "b")
+	which := Var[Incr[string]](g, a)
+	j := Join(g, which)
+	m := Map(g, j, ident)
+	o := MustObserve(g, m)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "a", o.Value())
+
+	// the join can't depend on a node that depends on the join.
+	which.Set(m)
+	err = g.Stabilize(ctx)
+	testutil.Error(t, err)
+
+	main := j.(*joinMainIncr[string])
+	testutil.Equal(t, true, main.join.rhs == Incr[string](a), "the previous inner incremental should be kept")
+	testutil.Equal(t, []INode{main.join.lhsChange, a}, main.Parents())
+	testutil.Equal(t, false, a.Node().forceNecessary)
+	testutil.Equal(t, 1, len(a.Node().children))
+	testutil.Equal(t, true, g.Has(a))
+	testutil.Equal(t, 0, len(m.Node().children), "the failed inner incremental should not stay linked to the join")
+
+	a.Set("not-a")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "not-a", o.Value())
+
+	which.Set(b)
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "b", o.Value())
+	testutil.Equal(t, false, g.Has(a))
+}
+
+func Test_Join_innerHeightChanges(t *testing.T) {
+	ctx := testContext()
+	g := New()
+
+	x := Var(g, "x")
+	deep := Var(g, false)
+	inner := Bind(g, deep, func(bs Scope, deep bool) Incr[string] {
+		if deep {
+			return Map(bs, Map(bs, Map(bs, x, ident), ident), func(vv string) string {
+				return vv + "-deep"
+			})
+		}
+		return x
+	})
+	which := Var[Incr[string]](g, inner)
+	j := Join(g, which)
+	o := MustObserve(g, Map(g, j, ident))
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "x", o.Value())
+	linkedHeight := inner.Node().height
+
+	deep.Set(true)
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "x-deep", o.Value())
+	testutil.Equal(t, true, inner.Node().height > linkedHeight, "the inner incremental should be taller once the bind changes")
+	testutil.Equal(t, true, j.Node().height > inner.Node().height)
+
+	x.Set("y")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "y-deep", o.Value())
+}
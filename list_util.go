@@ -1,11 +1,85 @@
 package incr
 
-func remove[A INode](nodes []A, id Identifier) []A {
-	var output []A
-	for _, n := range nodes {
-		if n.Node().id != id {
-			output = append(output, n)
+// indexedListThreshold is the length above which node lists start
+// tracking the index of each node so that adds and removes are
+// constant time instead of linear.
+const indexedListThreshold = 16
+
+// addIndexed adds nodes to a list of nodes, skipping nodes that are already
+// present, and returns the list and its index.
+//
+// The index is nil until the list grows past [indexedListThreshold], and is
+// then maintained by [addIndexed] and [removeIndexed].
+func addIndexed[A INode](nodes []A, index map[*Node]int, items ...A) ([]A, map[*Node]int) {
+	for _, item := range items {
+		in := item.Node()
+		if index != nil {
+			if _, ok := index[in]; ok {
+				continue
+			}
+			index[in] = len(nodes)
+			nodes = append(nodes, item)
+			continue
+		}
+		if indexOf(nodes, in) >= 0 {
+			continue
+		}
+		nodes = append(nodes, item)
+		if len(nodes) > indexedListThreshold {
+			index = make(map[*Node]int, len(nodes))
+			for x, n := range nodes {
+				index[n.Node()] = x
+			}
+		}
+	}
+	return nodes, index
+}
+
+// removeIndexed removes a node from a list of nodes in place, and
+// returns the list and its index.
+//
+// If the list is indexed the last node in the list is swapped into the removed
+// node's position, otherwise the order of the remaining nodes is preserved; in
+// either case the resulting order is deterministic.
+func removeIndexed[A INode](nodes []A, index map[*Node]int, item *Node) ([]A, map[*Node]int) {
+	var zero A
+	last := len(nodes) - 1
+	if index == nil {
+		position := indexOf(nodes, item)
+		if position < 0 {
+			return nodes, index
+		}
+		copy(nodes[position:], nodes[position+1:])
+		nodes[last] = zero
+		return nodes[:last], index
+	}
+	position, ok := index[item]
+	if !ok {
+		return nodes, index
+	}
+	delete(index, item)
+	if position != last {
+		nodes[position] = nodes[last]
+		index[nodes[position].Node()] = position
+	}
+	nodes[last] = zero
+	return nodes[:last], index
+}
+
+func indexOf[A INode](nodes []A, item *Node) int {
+	for x, n := range nodes {
+		if n.Node() == item {
+			return x
+		}
+	}
+	return -1
+}
+
+func indexOfID[A INode](nodes []A, id Identifier) int {
+	for x, n := range nodes {
+		if n.Node().ID() == id {
+			return x
 		}
 	}
-	return output
+	return -1
 }
@@ -6,7 +6,31 @@ import (
 	"github.com/wcharczuk/go-incr/testutil"
 )
 
-func Test_remove(t *testing.T) {
+func Test_addIndexed(t *testing.T) {
+	g := New()
+
+	n0 := newMockBareNode(g)
+	n1 := newMockBareNode(g)
+	nodes, index := addIndexed[INode](nil, nil, n0, n1, n0)
+	testutil.Equal(t, 2, len(nodes))
+	testutil.Nil(t, index)
+
+	for x := 0; x < indexedListThreshold; x++ {
+		nodes, index = addIndexed[INode](nodes, index, newMockBareNode(g))
+	}
+	testutil.Equal(t, indexedListThreshold+2, len(nodes))
+	testutil.NotNil(t, index)
+	testutil.Equal(t, len(nodes), len(index))
+	for x, n := range nodes {
+		testutil.Equal(t, x, index[n.Node()])
+	}
+
+	nodes, index = addIndexed[INode](nodes, index, n1)
+	testutil.Equal(t, indexedListThreshold+2, len(nodes))
+	testutil.Equal(t, len(nodes), len(index))
+}
+
+func Test_removeIndexed(t *testing.T) {
 	g := New()
 
 	n0 := newMockBareNode(g)
@@ -15,7 +39,39 @@ func Test_remove(t *testing.T) {
 	nodes := []INode{
 		n0, n1, n2,
 	}
-	nodes = remove(nodes, n1.Node().id)
+	nodes, index := removeIndexed(nodes, nil, n1.Node())
+	testutil.Equal(t, 2, len(nodes))
+	testutil.Nil(t, index)
+	testutil.Equal(t, n0.Node().ID(), nodes[0].Node().ID())
+	testutil.Equal(t, n2.Node().ID(), nodes[1].Node().ID())
 
+	nodes, _ = removeIndexed(nodes, nil, n1.Node())
 	testutil.Equal(t, 2, len(nodes))
 }
+
+func Test_removeIndexed_indexed(t *testing.T) {
+	g := New()
+
+	var nodes []INode
+	var index map[*Node]int
+	for x := 0; x < 4*indexedListThreshold; x++ {
+		nodes, index = addIndexed[INode](nodes, index, newMockBareNode(g))
+	}
+	first := nodes[0]
+	last := nodes[len(nodes)-1]
+
+	nodes, index = removeIndexed(nodes, index, first.Node())
+	testutil.Equal(t, 4*indexedListThreshold-1, len(nodes))
+	testutil.Equal(t, last.Node().ID(), nodes[0].Node().ID(), "the last node should be swapped into the removed position")
+
+	nodes, index = removeIndexed(nodes, index, first.Node())
+	testutil.Equal(t, 4*indexedListThreshold-1, len(nodes))
+
+	for len(nodes) > 0 {
+		nodes, index = removeIndexed(nodes, index, nodes[len(nodes)/2].Node())
+		testutil.Equal(t, len(nodes), len(index))
+		for x, n := range nodes {
+			testutil.Equal(t, x, index[n.Node()])
+		}
+	}
+}
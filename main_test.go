@@ -122,7 +122,6 @@ func (mn *mockBareNode) Value() any {
 func newHeightIncr(scope Scope, height int) *heightIncr {
 	return WithinScope(scope, &heightIncr{
 		n: &Node{
-			id:     NewIdentifier(),
 			height: height,
 		},
 	})
@@ -227,7 +226,7 @@ func dumpDot(g *Graph, path string) error {
 
 func hasKey[A INode](nodes []A, id Identifier) bool {
 	for _, n := range nodes {
-		if n.Node().id == id {
+		if n.Node().ID() == id {
 			return true
 		}
 	}
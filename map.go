@@ -25,6 +25,37 @@ func MapContext[A, B any](scope Scope, a Incr[A], fn func(context.Context, A) (B
 	})
 }
 
+// MapWithEqual applies a function to a given input incremental and returns a new
+// incremental of the output type of that function, using a given equality function
+// to cut off recomputation of its children.
+//
+// If the function returns a value that is equal to the previous value of the node, the
+// node does not change, keeping the previous value, and its children are not recomputed.
+//
+// For comparable values you can pass [Equals] as the equality function.
+func MapWithEqual[A, B any](scope Scope, a Incr[A], fn func(A) B, equal func(B, B) bool) Incr[B] {
+	return MapContextWithEqual(scope, a, func(_ context.Context, v A) (B, error) {
+		return fn(v), nil
+	}, equal)
+}
+
+// MapContextWithEqual applies a function to a given input incremental and returns a new
+// incremental of the output type of that function, using a given equality function
+// to cut off recomputation of its children, but is context aware and can also
+// return an error, aborting stabilization.
+//
+// If the function returns a value that is equal to the previous value of the node, the
+// node does not change, keeping the previous value, and its children are not recomputed.
+func MapContextWithEqual[A, B any](scope Scope, a Incr[A], fn func(context.Context, A) (B, error), equal func(B, B) bool) Incr[B] {
+	return WithinScope(scope, &mapEqualIncr[A, B]{
+		n:       NewNode("map"),
+		a:       a,
+		fn:      fn,
+		equal:   equal,
+		parents: []INode{a},
+	})
+}
+
 var (
 	_ Incr[string] = (*mapIncr[int, string])(nil)
 	_ INode        = (*mapIncr[int, string])(nil)
@@ -48,7 +79,10 @@ func (mn *mapIncr[A, B]) Node() *Node {
 	return mn.n
 }
 
-func (mn *mapIncr[A, B]) Value() B { return mn.val }
+func (mn *mapIncr[A, B]) Value() B {
+	mn.n.checkRead(mn)
+	return mn.val
+}
 
 func (mn *mapIncr[A, B]) Stabilize(ctx context.Context) (err error) {
 	var val B
@@ -63,3 +97,63 @@ func (mn *mapIncr[A, B]) Stabilize(ctx context.Context) (err error) {
 func (mn *mapIncr[A, B]) String() string {
 	return mn.n.String()
 }
+
+var (
+	_ Incr[string] = (*mapEqualIncr[int, string])(nil)
+	_ INode        = (*mapEqualIncr[int, string])(nil)
+	_ IStabilize   = (*mapEqualIncr[int, string])(nil)
+	_ ICutoff      = (*mapEqualIncr[int, string])(nil)
+	_ fmt.Stringer = (*mapEqualIncr[int, string])(nil)
+)
+
+// mapEqualIncr is a map node that computes its value in the cutoff
+// check so that it can compare the value to the previous value.
+type mapEqualIncr[A, B any] struct {
+	n     *Node
+	a     Incr[A]
+	fn    func(context.Context, A) (B, error)
+	equal func(B, B) bool
+	val   B
+	next  B
+	// hasValue is true once the node has computed a
+	// value, such that the first value is never cut off.
+	hasValue bool
+	parents  []INode
+}
+
+func (mn *mapEqualIncr[A, B]) Parents() []INode {
+	return mn.parents
+}
+
+func (mn *mapEqualIncr[A, B]) Node() *Node {
+	return mn.n
+}
+
+func (mn *mapEqualIncr[A, B]) Value() B {
+	mn.n.checkRead(mn)
+	return mn.val
+}
+
+func (mn *mapEqualIncr[A, B]) Cutoff(ctx context.Context) (bool, error) {
+	next, err := mn.fn(ctx, mn.a.Value())
+	if err != nil {
+		return false, err
+	}
+	if mn.hasValue && mn.equal(mn.val, next) {
+		return true, nil
+	}
+	mn.next = next
+	return false, nil
+}
+
+func (mn *mapEqualIncr[A, B]) Stabilize(_ context.Context) error {
+	var zero B
+	mn.val = mn.next
+	mn.next = zero
+	mn.hasValue = true
+	return nil
+}
+
+func (mn *mapEqualIncr[A, B]) String() string {
+	return mn.n.String()
+}
@@ -49,7 +49,10 @@ func (m2n *map2Incr[A, B, C]) Parents() []INode {
 
 func (m2n *map2Incr[A, B, C]) Node() *Node { return m2n.n }
 
-func (m2n *map2Incr[A, B, C]) Value() C { return m2n.val }
+func (m2n *map2Incr[A, B, C]) Value() C {
+	m2n.n.checkRead(m2n)
+	return m2n.val
+}
 
 func (m2n *map2Incr[A, B, C]) Stabilize(ctx context.Context) (err error) {
 	var val C
@@ -50,7 +50,10 @@ func (mn *map3Incr[A, B, C, D]) Parents() []INode {
 
 func (mn *map3Incr[A, B, C, D]) Node() *Node { return mn.n }
 
-func (mn *map3Incr[A, B, C, D]) Value() D { return mn.val }
+func (mn *map3Incr[A, B, C, D]) Value() D {
+	mn.n.checkRead(mn)
+	return mn.val
+}
 
 func (mn *map3Incr[A, B, C, D]) Stabilize(ctx context.Context) (err error) {
 	var val D
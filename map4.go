@@ -52,7 +52,10 @@ func (mn *map4Incr[A, B, C, D, E]) Parents() []INode {
 
 func (mn *map4Incr[A, B, C, D, E]) Node() *Node { return mn.n }
 
-func (mn *map4Incr[A, B, C, D, E]) Value() E { return mn.val }
+func (mn *map4Incr[A, B, C, D, E]) Value() E {
+	mn.n.checkRead(mn)
+	return mn.val
+}
 
 func (mn *map4Incr[A, B, C, D, E]) Stabilize(ctx context.Context) (err error) {
 	var val E
@@ -41,6 +41,7 @@ func (mi *mapIfIncr[T]) Parents() []INode {
 func (mi *mapIfIncr[A]) Node() *Node { return mi.n }
 
 func (mi *mapIfIncr[A]) Value() A {
+	mi.n.checkRead(mi)
 	return mi.value
 }
 
@@ -59,18 +59,25 @@ func (mi *mapNIncr[A, B]) Parents() []INode {
 }
 
 func (mn *mapNIncr[A, B]) AddInput(i Incr[A]) error {
+	graph := GraphForNode(mn)
+	if graph.lockStructureUnlessBuilding(mn.n.createdIn, i.Node().createdIn) {
+		defer graph.unlockStructure()
+	}
 	mn.inputs = append(mn.inputs, i)
 	if mn.n.height != HeightUnset {
 		// if we're already part of the graph, we have
 		// to tell the graph to update our parent<>child metadata
-		return GraphForNode(mn).addChild(mn, i)
+		return graph.addChild(mn, i)
 	}
 	return nil
 }
 
 func (mn *mapNIncr[A, B]) Node() *Node { return mn.n }
 
-func (mn *mapNIncr[A, B]) Value() B { return mn.val }
+func (mn *mapNIncr[A, B]) Value() B {
+	mn.n.checkRead(mn)
+	return mn.val
+}
 
 func (mn *mapNIncr[A, B]) Stabilize(ctx context.Context) (err error) {
 	var val B
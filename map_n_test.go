@@ -2,6 +2,7 @@ package incr
 
 import (
 	"testing"
+	"time"
 
 	"github.com/wcharczuk/go-incr/testutil"
 )
@@ -66,6 +67,73 @@ func Test_MapN_AddInput_afterStabilization(t *testing.T) {
 	testutil.Equal(t, 10, om.Value())
 }
 
+func Test_MapN_AddInput_withinBind_concurrent(t *testing.T) {
+	ctx := testContext()
+	g := New(OptGraphConcurrent(true))
+
+	bv := Var(g, 3)
+	b := Bind(g, bv, func(bs Scope, count int) Incr[int] {
+		mn := MapN(bs, sum[int])
+		for x := 1; x <= count; x++ {
+			testutil.NoError(t, mn.AddInput(Return(bs, x)))
+		}
+		return mn
+	})
+	om := MustObserve(g, b)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- g.Stabilize(ctx)
+	}()
+	select {
+	case err := <-done:
+		testutil.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("stabilization did not complete; adding inputs within a bind should not wait on stabilization")
+	}
+	testutil.Equal(t, 6, om.Value())
+}
+
+func Test_MapN_AddInput_duringParallelStabilize_concurrent(t *testing.T) {
+	ctx := testContext()
+	g := New(OptGraphConcurrent(true))
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	blocking := Map(g, Var(g, 1), func(v int) int {
+		close(started)
+		<-release
+		return v
+	})
+	mn := MapN(g, sum[int], blocking)
+	om := MustObserve(g, mn)
+
+	stabilized := make(chan error, 1)
+	go func() {
+		stabilized <- g.ParallelStabilize(ctx)
+	}()
+	<-started
+
+	added := make(chan error, 1)
+	go func() {
+		added <- mn.AddInput(Return(g, 10))
+	}()
+	select {
+	case <-added:
+		t.Fatal("adding an input from another goroutine should wait for the stabilization to complete")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+
+	testutil.NoError(t, <-stabilized)
+	testutil.NoError(t, <-added)
+	testutil.Equal(t, 1, om.Value())
+
+	err := g.ParallelStabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 11, om.Value())
+}
+
 func sum[A ~int | ~float64](values ...A) (out A) {
 	for _, v := range values {
 		out += v
@@ -0,0 +1,70 @@
+package incr
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/wcharczuk/go-incr/testutil"
+)
+
+func Test_MapWithEqual(t *testing.T) {
+	ctx := testContext()
+	g := New()
+	v := Var(g, 1)
+	parity := MapWithEqual(g, v, func(vv int) string {
+		if vv%2 == 0 {
+			return "even"
+		}
+		return "odd"
+	}, Equals)
+	var calls int
+	m := Map(g, parity, func(p string) string {
+		calls++
+		return "is " + p
+	})
+	o := MustObserve(g, m)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "is odd", o.Value())
+	testutil.Equal(t, 1, calls)
+	changedAt := parity.Node().changedAt
+
+	v.Set(3)
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "odd", parity.Value())
+	testutil.Equal(t, "is odd", o.Value())
+	testutil.Equal(t, 1, calls)
+	testutil.Equal(t, changedAt, parity.Node().changedAt)
+
+	v.Set(4)
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "even", parity.Value())
+	testutil.Equal(t, "is even", o.Value())
+	testutil.Equal(t, 2, calls)
+}
+
+func Test_MapContextWithEqual_error(t *testing.T) {
+	ctx := testContext()
+	g := New()
+	v := Var(g, 1)
+	m := MapContextWithEqual(g, v, func(_ context.Context, vv int) (int, error) {
+		if vv < 0 {
+			return 0, fmt.Errorf("negative value")
+		}
+		return vv * 2, nil
+	}, Equals)
+	o := MustObserve(g, m)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 2, o.Value())
+
+	v.Set(-1)
+	err = g.Stabilize(ctx)
+	testutil.Error(t, err)
+	testutil.Equal(t, 2, o.Value())
+}
@@ -3,12 +3,12 @@ package incr
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 )
 
 // NewNode returns a new node.
 func NewNode(kind string) *Node {
 	return &Node{
-		id:                        NewIdentifier(),
 		kind:                      kind,
 		valid:                     true, // start out valid!
 		height:                    HeightUnset,
@@ -26,8 +26,12 @@ const HeightUnset = -1
 type Node struct {
 	// createdIn is the "scope" the node was created in
 	createdIn Scope
-	// id is a unique identifier for the node
-	id Identifier
+	// id is a unique identifier for the node, and is
+	// assigned when it's first read with `ID()`.
+	id atomic.Pointer[Identifier]
+	// handle is the dense index of the node within its graph, and is
+	// assigned when the node is added to the graph; zero means unset.
+	handle uint32
 	// kind is the meta-type of the node
 	kind string
 	// metadata is any additional metadata a user wants to attach to a node.
@@ -47,6 +51,13 @@ type Node struct {
 	// observers are observer nodes that are attached to this
 	// node or its children.
 	sentinels []ISentinel
+	// parentsIndex, childrenIndex, observersIndex and sentinelsIndex
+	// track the position of each node in the respective lists once
+	// the lists are large enough to make linear removal expensive.
+	parentsIndex   map[*Node]int
+	childrenIndex  map[*Node]int
+	observersIndex map[*Node]int
+	sentinelsIndex map[*Node]int
 	// valid indicates if the scope that created the node is itself valid
 	valid bool
 	// forceNecessary forces the necessary state on the node
@@ -66,6 +77,9 @@ type Node struct {
 	// changedAt connotes when the node was changed last,
 	// specifically if any of the node's parents were set or bound
 	changedAt uint64
+	// changedTime is the start time, in unix nanoseconds, of
+	// the stabilization in which the node was changed last.
+	changedTime int64
 	// setAt connotes when the node was set last, specifically
 	// for var nodes so that we can track their "changed" state separately
 	// from their set state
@@ -95,6 +109,9 @@ type Node struct {
 	parentsFn func() []INode
 	// invalidateFn is a reference to the nodes invalidate function if present.
 	invalidateFn func()
+	// releaseFn is a reference to the nodes release function if present, and
+	// is called when the node stops being necessary or is invalidated.
+	releaseFn func()
 	// observer determines if we treat this as a special necessary state.
 	observer bool
 	// always determines if we always recompute this node.
@@ -103,6 +120,12 @@ type Node struct {
 	numRecomputes uint64
 	// numChanges is the number of times we changed the node
 	numChanges uint64
+	// costHint tells parallel stabilization how expensive
+	// the node is, and is set with `SetCostHint`.
+	costHint CostHint
+	// executor is the executor parallel stabilization should
+	// recompute the node with, and is set with `SetExecutor`.
+	executor Executor
 
 	nextInRecomputeHeap     INode
 	previousInRecomputeHeap INode
@@ -113,16 +136,27 @@ type Node struct {
 //
 
 // ID returns a unique identifier for the node.
+//
+// The identifier is assigned with [NewIdentifier] the first time it's
+// read, such that nodes whose identifiers are never read don't pay for
+// generating them.
 func (n *Node) ID() Identifier {
-	return n.id
+	if id := n.id.Load(); id != nil {
+		return *id
+	}
+	id := NewIdentifier()
+	if n.id.CompareAndSwap(nil, &id) {
+		return id
+	}
+	return *n.id.Load()
 }
 
 // String returns a string form of the node metadata.
 func (n *Node) String() string {
 	if n.label != "" {
-		return fmt.Sprintf("%s[%s]:%s@%d", n.kind, n.id.Short(), n.label, n.height)
+		return fmt.Sprintf("%s[%s]:%s@%d", n.kind, n.ID().Short(), n.label, n.height)
 	}
-	return fmt.Sprintf("%s[%s]@%d", n.kind, n.id.Short(), n.height)
+	return fmt.Sprintf("%s[%s]@%d", n.kind, n.ID().Short(), n.height)
 }
 
 // Set/Get properties
@@ -163,6 +197,34 @@ func (n *Node) SetMetadata(md any) {
 	n.metadata = md
 }
 
+// CostHint returns the cost hint for the node.
+func (n *Node) CostHint() CostHint {
+	return n.costHint
+}
+
+// SetCostHint sets the cost hint for the node, which determines if
+// parallel stabilization recomputes the node concurrently with other nodes
+// or on the stabilizing goroutine.
+//
+// The cost hint is not used by [Graph.Stabilize].
+func (n *Node) SetCostHint(hint CostHint) {
+	n.costHint = hint
+}
+
+// Executor returns the executor for the node, or nil if one hasn't been set.
+func (n *Node) Executor() Executor {
+	return n.executor
+}
+
+// SetExecutor sets the executor that parallel stabilization recomputes the node with,
+// e.g. if the node must be recomputed on a specific goroutine or thread.
+//
+// Nodes with an executor are recomputed concurrently with other nodes, and the
+// executor is not used by [Graph.Stabilize].
+func (n *Node) SetExecutor(executor Executor) {
+	n.executor = executor
+}
+
 // Kind returns the meta type of the node.
 func (n *Node) Kind() string {
 	return n.kind
@@ -183,6 +245,7 @@ func (n *Node) initializeFrom(in INode) {
 	n.detectCutoff(in)
 	n.detectInvalidate(in)
 	n.detectObserver(in)
+	n.detectRelease(in)
 	n.detectParents(in)
 	n.detectShouldBeInvalidated(in)
 	n.detectStabilize(in)
@@ -190,35 +253,35 @@ func (n *Node) initializeFrom(in INode) {
 }
 
 func (n *Node) addChildren(children ...INode) {
-	n.children = append(n.children, children...)
+	n.children, n.childrenIndex = addIndexed(n.children, n.childrenIndex, children...)
 }
 
 func (n *Node) addParents(parents ...INode) {
-	n.parents = append(n.parents, parents...)
+	n.parents, n.parentsIndex = addIndexed(n.parents, n.parentsIndex, parents...)
 }
 
 func (n *Node) addObservers(observers ...IObserver) {
-	n.observers = append(n.observers, observers...)
+	n.observers, n.observersIndex = addIndexed(n.observers, n.observersIndex, observers...)
 }
 
 func (n *Node) addSentinels(sentinels ...ISentinel) {
-	n.sentinels = append(n.sentinels, sentinels...)
+	n.sentinels, n.sentinelsIndex = addIndexed(n.sentinels, n.sentinelsIndex, sentinels...)
 }
 
-func (n *Node) removeChild(id Identifier) {
-	n.children = remove(n.children, id)
+func (n *Node) removeChild(item *Node) {
+	n.children, n.childrenIndex = removeIndexed(n.children, n.childrenIndex, item)
 }
 
-func (n *Node) removeParent(id Identifier) {
-	n.parents = remove(n.parents, id)
+func (n *Node) removeParent(item *Node) {
+	n.parents, n.parentsIndex = removeIndexed(n.parents, n.parentsIndex, item)
 }
 
-func (n *Node) removeObserver(id Identifier) {
-	n.observers = remove(n.observers, id)
+func (n *Node) removeObserver(item *Node) {
+	n.observers, n.observersIndex = removeIndexed(n.observers, n.observersIndex, item)
 }
 
-func (n *Node) removeSentinel(id Identifier) {
-	n.sentinels = remove(n.sentinels, id)
+func (n *Node) removeSentinel(item *Node) {
+	n.sentinels, n.sentinelsIndex = removeIndexed(n.sentinels, n.sentinelsIndex, item)
 }
 
 // maybeCutoff calls the cutoff delegate if it's set, otherwise
@@ -252,6 +315,12 @@ func (n *Node) detectInvalidate(gn INode) {
 	}
 }
 
+func (n *Node) detectRelease(gn INode) {
+	if typed, ok := gn.(releaser); ok {
+		n.releaseFn = typed.release
+	}
+}
+
 func (n *Node) detectObserver(gn INode) {
 	_, n.observer = gn.(IObserver)
 }
@@ -274,6 +343,12 @@ func (n *Node) detectShouldBeInvalidated(gn INode) {
 	}
 }
 
+func (n *Node) maybeRelease() {
+	if n.releaseFn != nil {
+		n.releaseFn()
+	}
+}
+
 func (n *Node) maybeInvalidate() {
 	if n.invalidateFn != nil {
 		n.invalidateFn()
@@ -332,6 +407,17 @@ func (n *Node) isStale() bool {
 	return n.recomputedAt == 0 || n.isStaleInRespectToParent()
 }
 
+// checkRead calls the graph's unnecessary read handler with the
+// node if the handler is set and the node is not necessary.
+func (n *Node) checkRead(gn INode) {
+	if n.isNecessary() || n.createdIn == nil {
+		return
+	}
+	if handler := n.createdIn.scopeGraph().onUnnecessaryRead; handler != nil {
+		handler(gn)
+	}
+}
+
 func (n *Node) isNecessary() bool {
 	if n.observer {
 		return true
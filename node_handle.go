@@ -0,0 +1,61 @@
+package incr
+
+import "cmp"
+
+// ensureHandle returns the handle for a given node, assigning
+// the node the next available handle if it doesn't have one.
+//
+// Handles are dense indexes that are reused once the node they're
+// assigned to is removed from the graph, such that the lists
+// indexed by handle stay proportional to the size of the graph.
+func (graph *Graph) ensureHandle(n *Node) uint32 {
+	if n.handle != 0 {
+		return n.handle
+	}
+	graph.handlesMu.Lock()
+	defer graph.handlesMu.Unlock()
+	if last := len(graph.freeHandles) - 1; last >= 0 {
+		n.handle = graph.freeHandles[last]
+		graph.freeHandles = graph.freeHandles[:last]
+		return n.handle
+	}
+	// handles start at 1 so that the zero
+	// value denotes an unassigned handle.
+	graph.nextHandle++
+	n.handle = graph.nextHandle
+	return n.handle
+}
+
+// releaseHandle returns a node's handle to the graph to be reused.
+func (graph *Graph) releaseHandle(n *Node) {
+	if n.handle == 0 {
+		return
+	}
+	graph.handlesMu.Lock()
+	graph.freeHandles = append(graph.freeHandles, n.handle)
+	graph.handlesMu.Unlock()
+	n.handle = 0
+}
+
+// compareHandles compares two nodes by handle.
+func compareHandles[A INode](a, b A) int {
+	return cmp.Compare(a.Node().handle, b.Node().handle)
+}
+
+// hasHandle returns if a list indexed by handle holds a given node.
+func hasHandle[A INode](items []A, n *Node) bool {
+	if n.handle == 0 || int(n.handle) >= len(items) {
+		return false
+	}
+	item := items[n.handle]
+	return any(item) != nil && item.Node() == n
+}
+
+// setHandle sets an item in a list indexed by handle, growing the list if required.
+func setHandle[A any](items []A, handle uint32, item A) []A {
+	if index := int(handle); index >= len(items) {
+		items = append(items, make([]A, index+1-len(items))...)
+	}
+	items[handle] = item
+	return items
+}
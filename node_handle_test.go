@@ -0,0 +1,70 @@
+package incr
+
+import (
+	"testing"
+
+	"github.com/wcharczuk/go-incr/testutil"
+)
+
+func Test_Graph_ensureHandle(t *testing.T) {
+	g := New()
+
+	n0 := NewNode("test")
+	n1 := NewNode("test")
+	testutil.Equal(t, 0, n0.handle)
+
+	testutil.Equal(t, 1, g.ensureHandle(n0))
+	testutil.Equal(t, 1, g.ensureHandle(n0))
+	testutil.Equal(t, 2, g.ensureHandle(n1))
+
+	g.releaseHandle(n0)
+	testutil.Equal(t, 0, n0.handle)
+	g.releaseHandle(n0)
+	testutil.Equal(t, 1, len(g.freeHandles))
+
+	n2 := NewNode("test")
+	testutil.Equal(t, 1, g.ensureHandle(n2), "released handles should be reused")
+	testutil.Equal(t, 0, len(g.freeHandles))
+	testutil.Equal(t, 3, g.ensureHandle(n0))
+}
+
+func Test_Graph_handles_reusedAfterUnobserve(t *testing.T) {
+	g := New()
+
+	v := Var(g, "foo")
+	m := Map(g, v, ident)
+	o := MustObserve(g, m)
+	testutil.Equal(t, true, g.Has(m))
+	testutil.Equal(t, true, g.HasObserver(o))
+
+	handle := m.Node().handle
+	testutil.NotEqual(t, 0, handle)
+
+	o.Unobserve(testContext())
+	testutil.Equal(t, false, g.Has(m))
+	testutil.Equal(t, false, g.HasObserver(o))
+	testutil.Equal(t, 0, m.Node().handle)
+	testutil.Equal(t, 3, len(g.freeHandles))
+
+	v1 := Var(g, "bar")
+	_ = MustObserve(g, v1)
+	testutil.Equal(t, true, g.Has(v1))
+	testutil.Equal(t, false, g.Has(m))
+	testutil.Equal(t, 1, len(g.freeHandles))
+}
+
+func Test_Graph_Has_otherGraph(t *testing.T) {
+	g0 := New()
+	g1 := New()
+
+	v0 := Var(g0, "foo")
+	_ = MustObserve(g0, v0)
+	v1 := Var(g1, "bar")
+	_ = MustObserve(g1, v1)
+
+	testutil.Equal(t, v0.Node().handle, v1.Node().handle)
+	testutil.Equal(t, true, g0.Has(v0))
+	testutil.Equal(t, false, g0.Has(v1))
+	testutil.Equal(t, true, g1.Has(v1))
+	testutil.Equal(t, false, g1.Has(v0))
+}
@@ -11,7 +11,7 @@ import (
 
 func Test_NewNode(t *testing.T) {
 	n := NewNode("test_node")
-	testutil.NotNil(t, n.id)
+	testutil.Nil(t, n.id.Load(), "the id should be assigned lazily")
 	testutil.Equal(t, "test_node", n.kind)
 	testutil.Equal(t, 0, len(n.parents))
 	testutil.Equal(t, 0, len(n.children))
@@ -35,7 +35,7 @@ func Test_Node_ID(t *testing.T) {
 	testutil.Equal(t, false, n.ID().IsZero())
 
 	other := NewIdentifier()
-	n.id = other
+	n.id.Store(&other)
 	testutil.Equal(t, other, n.ID())
 }
 
@@ -53,16 +53,30 @@ func Test_Node_Metadata(t *testing.T) {
 	testutil.Equal(t, "foo", n.Metadata())
 }
 
+func Test_Node_CostHint(t *testing.T) {
+	n := NewNode("test_node")
+	testutil.Equal(t, CostHintDefault, n.CostHint())
+	n.SetCostHint(CostHintExpensive)
+	testutil.Equal(t, CostHintExpensive, n.CostHint())
+}
+
+func Test_Node_Executor(t *testing.T) {
+	n := NewNode("test_node")
+	testutil.Nil(t, n.Executor())
+	n.SetExecutor(ExecutorFunc(func(fn func()) { fn() }))
+	testutil.NotNil(t, n.Executor())
+}
+
 func Test_Node_String(t *testing.T) {
 	g := New()
 
 	n := newMockBareNode(g)
 	n.n.height = 2
 
-	testutil.Equal(t, "bare_node["+n.n.id.Short()+"]@2", n.Node().String())
+	testutil.Equal(t, "bare_node["+n.n.ID().Short()+"]@2", n.Node().String())
 
 	n.Node().SetLabel("test_label")
-	testutil.Equal(t, "bare_node["+n.n.id.Short()+"]:test_label@2", n.Node().String())
+	testutil.Equal(t, "bare_node["+n.n.ID().Short()+"]:test_label@2", n.Node().String())
 }
 
 func Test_SetStale(t *testing.T) {
@@ -135,8 +149,8 @@ func Test_Node_addChildren(t *testing.T) {
 	testutil.Equal(t, 0, len(n.n.parents))
 	testutil.Equal(t, 2, len(n.n.children))
 
-	testutil.Equal(t, true, hasKey(n.n.children, c0.n.id))
-	testutil.Equal(t, true, hasKey(n.n.children, c1.n.id))
+	testutil.Equal(t, true, hasKey(n.n.children, c0.n.ID()))
+	testutil.Equal(t, true, hasKey(n.n.children, c1.n.ID()))
 }
 
 func Test_Node_removeChild(t *testing.T) {
@@ -159,17 +173,17 @@ func Test_Node_removeChild(t *testing.T) {
 	testutil.Equal(t, 0, len(n.n.parents))
 	testutil.Equal(t, 3, len(n.n.children))
 
-	testutil.Equal(t, true, hasKey(n.n.children, c0.n.id))
-	testutil.Equal(t, true, hasKey(n.n.children, c1.n.id))
-	testutil.Equal(t, true, hasKey(n.n.children, c2.n.id))
+	testutil.Equal(t, true, hasKey(n.n.children, c0.n.ID()))
+	testutil.Equal(t, true, hasKey(n.n.children, c1.n.ID()))
+	testutil.Equal(t, true, hasKey(n.n.children, c2.n.ID()))
 
-	n.Node().removeChild(c1.n.id)
+	n.Node().removeChild(c1.n)
 
 	testutil.Equal(t, 0, len(n.n.parents))
 	testutil.Equal(t, 2, len(n.n.children))
-	testutil.Equal(t, true, hasKey(n.n.children, c0.n.id))
-	testutil.Equal(t, false, hasKey(n.n.children, c1.n.id))
-	testutil.Equal(t, true, hasKey(n.n.children, c2.n.id))
+	testutil.Equal(t, true, hasKey(n.n.children, c0.n.ID()))
+	testutil.Equal(t, false, hasKey(n.n.children, c1.n.ID()))
+	testutil.Equal(t, true, hasKey(n.n.children, c2.n.ID()))
 }
 
 func Test_Node_addParents(t *testing.T) {
@@ -192,8 +206,8 @@ func Test_Node_addParents(t *testing.T) {
 	testutil.Equal(t, 2, len(n.n.parents))
 	testutil.Equal(t, 0, len(n.n.children))
 
-	testutil.Equal(t, true, hasKey(n.n.parents, c0.n.id))
-	testutil.Equal(t, true, hasKey(n.n.parents, c1.n.id))
+	testutil.Equal(t, true, hasKey(n.n.parents, c0.n.ID()))
+	testutil.Equal(t, true, hasKey(n.n.parents, c1.n.ID()))
 }
 
 func Test_Node_removeParent(t *testing.T) {
@@ -216,18 +230,18 @@ func Test_Node_removeParent(t *testing.T) {
 	testutil.Equal(t, 3, len(n.n.parents))
 	testutil.Equal(t, 0, len(n.n.children))
 
-	testutil.Equal(t, true, hasKey(n.n.parents, c0.n.id))
-	testutil.Equal(t, true, hasKey(n.n.parents, c1.n.id))
-	testutil.Equal(t, true, hasKey(n.n.parents, c2.n.id))
+	testutil.Equal(t, true, hasKey(n.n.parents, c0.n.ID()))
+	testutil.Equal(t, true, hasKey(n.n.parents, c1.n.ID()))
+	testutil.Equal(t, true, hasKey(n.n.parents, c2.n.ID()))
 
-	n.Node().removeParent(c1.n.id)
+	n.Node().removeParent(c1.n)
 
 	testutil.Equal(t, 2, len(n.n.parents))
 	testutil.Equal(t, 0, len(n.n.children))
 
-	testutil.Equal(t, true, hasKey(n.n.parents, c0.n.id))
-	testutil.Equal(t, false, hasKey(n.n.parents, c1.n.id))
-	testutil.Equal(t, true, hasKey(n.n.parents, c2.n.id))
+	testutil.Equal(t, true, hasKey(n.n.parents, c0.n.ID()))
+	testutil.Equal(t, false, hasKey(n.n.parents, c1.n.ID()))
+	testutil.Equal(t, true, hasKey(n.n.parents, c2.n.ID()))
 }
 
 func Test_Node_addObserver(t *testing.T) {
@@ -243,8 +257,8 @@ func Test_Node_addObserver(t *testing.T) {
 
 	testutil.Equal(t, 2, len(n.n.observers))
 
-	testutil.Equal(t, true, hasKey(n.n.observers, o0.Node().id))
-	testutil.Equal(t, true, hasKey(n.n.observers, o1.Node().id))
+	testutil.Equal(t, true, hasKey(n.n.observers, o0.Node().ID()))
+	testutil.Equal(t, true, hasKey(n.n.observers, o1.Node().ID()))
 }
 
 func Test_Node_removeObservers(t *testing.T) {
@@ -260,12 +274,12 @@ func Test_Node_removeObservers(t *testing.T) {
 
 	testutil.Equal(t, 2, len(n.n.observers))
 
-	n.Node().removeObserver(o1.Node().id)
+	n.Node().removeObserver(o1.Node())
 
 	testutil.Equal(t, 1, len(n.n.observers))
 
-	testutil.Equal(t, true, hasKey(n.n.observers, o0.Node().id))
-	testutil.Equal(t, false, hasKey(n.n.observers, o1.Node().id))
+	testutil.Equal(t, true, hasKey(n.n.observers, o0.Node().ID()))
+	testutil.Equal(t, false, hasKey(n.n.observers, o1.Node().ID()))
 }
 
 func Test_Node_maybeStabilize(t *testing.T) {
@@ -475,7 +489,7 @@ func Test_nodeFormatters(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		tc.Node.Node().id = id
+		ExpertNode(tc.Node).SetID(id)
 		tc.Node.Node().height = 2
 		testutil.Equal(t, fmt.Sprintf("%s[%s]@2", tc.Label, id.Short()), fmt.Sprint(tc.Node))
 		tc.Node.Node().label = "test-label"
@@ -501,19 +515,19 @@ func Test_nodeSorter(t *testing.T) {
 
 	a := newMockBareNode(g)
 	a.Node().height = 1
-	a.Node().id, _ = ParseIdentifier(strings.Repeat("0", 32))
+	ExpertNode(a).SetID(MustParseIdentifier(strings.Repeat("0", 32)))
 
 	b := newMockBareNode(g)
 	b.Node().height = 1
-	b.Node().id, _ = ParseIdentifier(strings.Repeat("1", 32))
+	ExpertNode(b).SetID(MustParseIdentifier(strings.Repeat("1", 32)))
 
 	c := newMockBareNode(g)
 	c.Node().height = 1
-	c.Node().id, _ = ParseIdentifier(strings.Repeat("2", 32))
+	ExpertNode(c).SetID(MustParseIdentifier(strings.Repeat("2", 32)))
 
 	d := newMockBareNode(g)
 	d.Node().height = 2
-	d.Node().id, _ = ParseIdentifier(strings.Repeat("3", 32))
+	ExpertNode(d).SetID(MustParseIdentifier(strings.Repeat("3", 32)))
 
 	testutil.Equal(t, true, "00000000000000000000000000000000" < "11111111111111111111111111111111")
 	testutil.Equal(t, 0, nodeSorter(a, a))
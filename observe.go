@@ -3,14 +3,16 @@ package incr
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
+	"time"
 )
 
 // MustObserve observes a node, specifically including it for computation
 // as well as all of its parents.
 //
 // If this detects a cycle or any other issue a panic will be raised.
-func MustObserve[A any](g *Graph, observed Incr[A]) ObserveIncr[A] {
-	o, err := Observe[A](g, observed)
+func MustObserve[A any](g *Graph, observed Incr[A], opts ...ObserveOption[A]) ObserveIncr[A] {
+	o, err := Observe[A](g, observed, opts...)
 	if err != nil {
 		panic(err)
 	}
@@ -19,17 +21,48 @@ func MustObserve[A any](g *Graph, observed Incr[A]) ObserveIncr[A] {
 
 // Observe observes a node, specifically including it for computation
 // as well as all of its parents.
-func Observe[A any](g *Graph, observed Incr[A]) (ObserveIncr[A], error) {
+func Observe[A any](g *Graph, observed Incr[A], opts ...ObserveOption[A]) (ObserveIncr[A], error) {
+	var options ObserveOptions[A]
+	for _, opt := range opts {
+		opt(&options)
+	}
 	o := WithinScope(g, &observeIncr[A]{
 		n:        NewNode("observer"),
 		observed: observed,
+		equal:    options.Equal,
 	})
+	g.lockStructure()
+	defer g.unlockStructure()
 	if err := g.observeNode(o, observed); err != nil {
 		return nil, err
 	}
+	o.observedAt = g.stabilizationNum
+	if on := observed.Node(); on.changedAt > 0 {
+		o.setChangedAt(on.changedAt, on.changedTime)
+	}
 	return o, nil
 }
 
+// ObserveOption mutates ObserveOptions.
+type ObserveOption[A any] func(*ObserveOptions[A])
+
+// OptObserveEqual sets a function the observer uses to compare the observed value
+// to the value it had when it last changed.
+//
+// If the values are equal, the change handlers registered with [ObserveIncr.OnChange]
+// are not called, and the observer is not passed to the handlers registered
+// with [Graph.OnObserversChanged].
+func OptObserveEqual[A any](equal func(A, A) bool) ObserveOption[A] {
+	return func(o *ObserveOptions[A]) {
+		o.Equal = equal
+	}
+}
+
+// ObserveOptions are options for observers.
+type ObserveOptions[A any] struct {
+	Equal func(A, A) bool
+}
+
 // ObserveIncr is an incremental that observes a graph
 // of incrementals starting a given input.
 type ObserveIncr[A any] interface {
@@ -43,6 +76,49 @@ type ObserveIncr[A any] interface {
 	// will also be called serially, conversely if the stabilization is "paralllel"
 	// all update handlers will be called in parallel using the graph worker pool.
 	OnUpdate(func(context.Context, A))
+	// OnChange lets you register a change handler for the observer node.
+	//
+	// This handler is called after stabilization when the observed node changed, and
+	// is passed the value the observer had when it last changed (the zero value the
+	// first time), and the new value.
+	//
+	// If the observer was created with [OptObserveEqual], the handler
+	// is not called if the old and new values are equal.
+	OnChange(func(ctx context.Context, old, new A))
+	// Snapshot returns the observed value as of the end of the last successful stabilization
+	// that changed it, and the number of the stabilization that published it.
+	//
+	// Snapshot is safe to call from any goroutine, including while the graph
+	// is stabilizing, and does not take a graph lock.
+	//
+	// Snapshots are only published if the graph was created with [OptGraphObserverSnapshots],
+	// otherwise, and before the first stabilization, Snapshot returns the zero value and zero.
+	Snapshot() (A, uint64)
+	// IsStale returns if the observed value may not reflect the latest sets, that is
+	// if the observed node or any of its ancestors needs to be recomputed, or has
+	// a value set during stabilization that will be applied after.
+	//
+	// Nodes that are recomputed for every stabilization (e.g. [Always] and sentinels)
+	// don't make the observed value stale unless they're marked stale.
+	//
+	// IsStale returns true while the graph is stabilizing, and if the graph is not
+	// concurrent it must not be called from other goroutines during stabilization.
+	IsStale() bool
+	// LastChangedAt returns the stabilization number, and the time that stabilization
+	// started, of the stabilization in which the observed node last changed.
+	//
+	// LastChangedAt returns zero and the zero time if the observed node hasn't changed.
+	LastChangedAt() (uint64, time.Time)
+	// LastStabilizedAt returns the stabilization number, and the time that stabilization
+	// started, of the last stabilization that completed without an error since the
+	// observer was created.
+	//
+	// LastStabilizedAt returns zero and the zero time if no stabilization has completed
+	// since the observer was created.
+	LastStabilizedAt() (uint64, time.Time)
+	// OnUnobserve lets you register a handler that is called when
+	// the observer is unobserved with [IObserver.Unobserve].
+	OnUnobserve(func(context.Context))
 	// Value returns the observed node value.
 	Value() A
 }
@@ -61,13 +137,61 @@ type IObserver interface {
 }
 
 var (
-	_ ObserveIncr[any] = (*observeIncr[any])(nil)
-	_ fmt.Stringer     = (*observeIncr[any])(nil)
+	_ ObserveIncr[any]  = (*observeIncr[any])(nil)
+	_ fmt.Stringer      = (*observeIncr[any])(nil)
+	_ observerChange    = (*observeIncr[any])(nil)
+	_ observerSnapshot  = (*observeIncr[any])(nil)
+	_ observerChangedAt = (*observeIncr[any])(nil)
 )
 
+// observerChangedAt is an observer that tracks when its observed node changed.
+type observerChangedAt interface {
+	IObserver
+	setChangedAt(stabilizationNum uint64, unixNano int64)
+}
+
+// observerChange is an observer that can check if its value changed.
+type observerChange interface {
+	IObserver
+	hasObservedChange(stabilizationNum uint64) bool
+}
+
+// observerSnapshot is an observer that can publish a snapshot of its value.
+type observerSnapshot interface {
+	IObserver
+	publishSnapshot(stabilizationNum uint64)
+}
+
+// snapshot is an immutable observed value.
+type snapshot[A any] struct {
+	value            A
+	stabilizationNum uint64
+}
+
 type observeIncr[A any] struct {
-	n        *Node
-	observed Incr[A]
+	n                   *Node
+	observed            Incr[A]
+	onUnobserveHandlers []func(context.Context)
+	onChangeHandlers    []func(context.Context, A, A)
+
+	// equal is an optional function used to check if the observed value changed.
+	equal func(A, A) bool
+	// last is the observed value as of the last stabilization the observer changed.
+	last A
+	// previous is the observed value before the change checked in changeCheckedAt.
+	previous        A
+	changed         bool
+	changeCheckedAt uint64
+
+	// snapshot is the last published snapshot of the observed value.
+	snapshot atomic.Pointer[snapshot[A]]
+
+	// observedAt is the number of the first stabilization after the observer was created.
+	observedAt uint64
+	// changedAt and changedTime are the stabilization number and start time, in unix
+	// nanoseconds, of the stabilization the observed node last changed in.
+	changedAt   atomic.Uint64
+	changedTime atomic.Int64
 }
 
 func (o *observeIncr[A]) OnUpdate(fn func(context.Context, A)) {
@@ -76,6 +200,109 @@ func (o *observeIncr[A]) OnUpdate(fn func(context.Context, A)) {
 	})
 }
 
+func (o *observeIncr[A]) OnChange(fn func(context.Context, A, A)) {
+	if len(o.onChangeHandlers) == 0 {
+		o.n.OnUpdate(o.runChangeHandlers)
+	}
+	o.onChangeHandlers = append(o.onChangeHandlers, fn)
+}
+
+func (o *observeIncr[A]) runChangeHandlers(ctx context.Context) {
+	old, changed := o.observedChange(atomic.LoadUint64(&GraphForNode(o).stabilizationNum))
+	if !changed {
+		return
+	}
+	value := o.Value()
+	for _, handler := range o.onChangeHandlers {
+		handler(ctx, old, value)
+	}
+}
+
+// observedChange returns the observed value before a given stabilization and if the value
+// changed, comparing the value to the last value at most once per stabilization.
+func (o *observeIncr[A]) observedChange(stabilizationNum uint64) (old A, changed bool) {
+	if o.changeCheckedAt != stabilizationNum {
+		value := o.Value()
+		o.changed = o.equal == nil || !o.equal(o.last, value)
+		if o.changed {
+			o.previous = o.last
+			o.last = value
+		}
+		o.changeCheckedAt = stabilizationNum
+	}
+	return o.previous, o.changed
+}
+
+func (o *observeIncr[A]) hasObservedChange(stabilizationNum uint64) bool {
+	_, changed := o.observedChange(stabilizationNum)
+	return changed
+}
+
+func (o *observeIncr[A]) IsStale() bool {
+	graph := GraphForNode(o)
+	if graph.concurrent {
+		graph.stateMu.Lock()
+		defer graph.stateMu.Unlock()
+	}
+	if atomic.LoadInt32(&graph.status) == StatusStabilizing {
+		return true
+	}
+	if o.observed == nil {
+		return false
+	}
+	return graph.hasStaleAncestor(o.observed)
+}
+
+func (o *observeIncr[A]) LastChangedAt() (stabilizationNum uint64, at time.Time) {
+	stabilizationNum = o.changedAt.Load()
+	if stabilizationNum == 0 {
+		return
+	}
+	at = time.Unix(0, o.changedTime.Load())
+	return
+}
+
+func (o *observeIncr[A]) LastStabilizedAt() (stabilizationNum uint64, at time.Time) {
+	graph := GraphForNode(o)
+	stabilizationNum = atomic.LoadUint64(&graph.lastStabilizedNum)
+	if stabilizationNum == 0 || stabilizationNum < o.observedAt {
+		return 0, time.Time{}
+	}
+	at = time.Unix(0, atomic.LoadInt64(&graph.lastStabilizedTime))
+	return
+}
+
+func (o *observeIncr[A]) setChangedAt(stabilizationNum uint64, unixNano int64) {
+	o.changedTime.Store(unixNano)
+	o.changedAt.Store(stabilizationNum)
+}
+
+func (o *observeIncr[A]) Snapshot() (value A, stabilizationNum uint64) {
+	if s := o.snapshot.Load(); s != nil {
+		value, stabilizationNum = s.value, s.stabilizationNum
+	}
+	return
+}
+
+// publishSnapshot publishes a snapshot of the observed value if the observed node
+// changed since the last snapshot was published.
+func (o *observeIncr[A]) publishSnapshot(stabilizationNum uint64) {
+	if o.observed == nil {
+		return
+	}
+	if current := o.snapshot.Load(); current != nil && o.observed.Node().changedAt <= current.stabilizationNum {
+		return
+	}
+	o.snapshot.Store(&snapshot[A]{
+		value:            o.observed.Value(),
+		stabilizationNum: stabilizationNum,
+	})
+}
+
+func (o *observeIncr[A]) OnUnobserve(fn func(context.Context)) {
+	o.onUnobserveHandlers = append(o.onUnobserveHandlers, fn)
+}
+
 func (o *observeIncr[A]) Node() *Node { return o.n }
 
 // Unobserve effectively removes a given node from the observed ref count for a graph.
@@ -85,8 +312,17 @@ func (o *observeIncr[A]) Node() *Node { return o.n }
 //
 // To observe parts of a graph again, use the `MustObserve(...)` helper.
 func (o *observeIncr[A]) Unobserve(ctx context.Context) {
-	GraphForNode(o).unobserveNode(o, o.observed)
+	graph := GraphForNode(o)
+	graph.lockStructure()
+	graph.unobserveNode(o, o.observed)
 	o.observed = nil
+	handlers := o.onUnobserveHandlers
+	o.onUnobserveHandlers = nil
+	graph.unlockStructure()
+
+	for _, handler := range handlers {
+		handler(ctx)
+	}
 }
 
 func (o *observeIncr[A]) Value() (output A) {
@@ -98,7 +334,7 @@ func (o *observeIncr[A]) Value() (output A) {
 
 func (o *observeIncr[A]) String() string {
 	if o.n.label != "" {
-		return fmt.Sprintf("%s[%s]:%s", o.n.kind, o.n.id.Short(), o.n.label)
+		return fmt.Sprintf("%s[%s]:%s", o.n.kind, o.n.ID().Short(), o.n.label)
 	}
-	return fmt.Sprintf("%s[%s]", o.n.kind, o.n.id.Short())
+	return fmt.Sprintf("%s[%s]", o.n.kind, o.n.ID().Short())
 }
@@ -3,6 +3,9 @@ package incr
 import (
 	"context"
 	"fmt"
+	"runtime"
+	"slices"
+	"sync"
 	"testing"
 
 	"github.com/wcharczuk/go-incr/testutil"
@@ -217,3 +220,318 @@ func Test_Observe_onUpdate(t *testing.T) {
 	testutil.Equal(t, 2, updateCalls)
 	testutil.Equal(t, []string{"foo", "not-foo"}, gotValues)
 }
+
+func Test_Observe_onUnobserve(t *testing.T) {
+	ctx := testContext()
+	g := New()
+	v := Var(g, "foo")
+	m0 := Map(g, v, ident)
+	o := MustObserve(g, m0)
+
+	var unobserveCalls int
+	o.OnUnobserve(func(ctx context.Context) {
+		testutil.BlueDye(ctx, t)
+		testutil.Equal(t, false, g.Has(m0), "handlers should be called after the observer is removed")
+		unobserveCalls++
+	})
+
+	o.Unobserve(ctx)
+	testutil.Equal(t, 1, unobserveCalls)
+}
+
+func Test_Observe_onUpdate_multipleObservers(t *testing.T) {
+	ctx := testContext()
+	g := New()
+	v := Var(g, "foo")
+	m := Map(g, v, ident)
+
+	var updates []string
+	m.Node().OnUpdate(func(_ context.Context) {
+		updates = append(updates, "node")
+	})
+	o0 := MustObserve(g, m)
+	o0.OnUpdate(func(_ context.Context, value string) {
+		updates = append(updates, "o0:"+value)
+	})
+	o1 := MustObserve(g, m)
+	o1.OnUpdate(func(_ context.Context, value string) {
+		updates = append(updates, "o1:"+value)
+	})
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	slices.Sort(updates)
+	testutil.Equal(t, []string{"node", "o0:foo", "o1:foo"}, updates)
+}
+
+func Test_Observe_onChange(t *testing.T) {
+	ctx := testContext()
+	g := New()
+	v := Var(g, "foo")
+	m := Map(g, v, ident)
+
+	type change struct{ old, new string }
+	var changes, equalChanges []change
+	o := MustObserve(g, m)
+	o.OnChange(func(_ context.Context, old, new string) {
+		changes = append(changes, change{old, new})
+	})
+	oe := MustObserve(g, m, OptObserveEqual(Equals[string]))
+	oe.OnChange(func(_ context.Context, old, new string) {
+		equalChanges = append(equalChanges, change{old, new})
+	})
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, []change{{"", "foo"}}, changes)
+	testutil.Equal(t, []change{{"", "foo"}}, equalChanges)
+
+	v.Set("foo")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, []change{{"", "foo"}, {"foo", "foo"}}, changes)
+	testutil.Equal(t, []change{{"", "foo"}}, equalChanges, "equal values should be suppressed")
+
+	v.Set("bar")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, []change{{"", "foo"}, {"foo", "foo"}, {"foo", "bar"}}, changes)
+	testutil.Equal(t, []change{{"", "foo"}, {"foo", "bar"}}, equalChanges)
+}
+
+func Test_Observe_Snapshot(t *testing.T) {
+	ctx := testContext()
+	g := New(OptGraphObserverSnapshots(true))
+	v := Var(g, "foo")
+	m := Map(g, v, ident)
+	o := MustObserve(g, m)
+
+	value, stabilizationNum := o.Snapshot()
+	testutil.Equal(t, "", value)
+	testutil.Equal(t, 0, stabilizationNum)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	value, stabilizationNum = o.Snapshot()
+	testutil.Equal(t, "foo", value)
+	testutil.Equal(t, 1, stabilizationNum)
+
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	value, stabilizationNum = o.Snapshot()
+	testutil.Equal(t, "foo", value)
+	testutil.Equal(t, 1, stabilizationNum)
+
+	v.Set("bar")
+	value, _ = o.Snapshot()
+	testutil.Equal(t, "foo", value, "sets should not be visible until stabilization")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	value, stabilizationNum = o.Snapshot()
+	testutil.Equal(t, "bar", value)
+	testutil.Equal(t, 3, stabilizationNum)
+}
+
+func Test_Observe_Snapshot_notEnabled(t *testing.T) {
+	ctx := testContext()
+	g := New()
+	v := Var(g, "foo")
+	o := MustObserve(g, Map(g, v, ident))
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	value, stabilizationNum := o.Snapshot()
+	testutil.Equal(t, "", value)
+	testutil.Equal(t, 0, stabilizationNum)
+}
+
+func Test_Observe_Snapshot_concurrentReads(t *testing.T) {
+	ctx := testContext()
+	g := New(OptGraphObserverSnapshots(true), OptGraphParallelism(4))
+	v := Var(g, 0)
+	m := Map(g, v, func(vv int) [2]int {
+		runtime.Gosched()
+		return [2]int{vv, vv * 2}
+	})
+	o := MustObserve(g, m)
+
+	done := make(chan struct{})
+	var started, readers sync.WaitGroup
+	for x := 0; x < 4; x++ {
+		started.Add(1)
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			started.Done()
+			var lastStabilizationNum uint64
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				value, stabilizationNum := o.Snapshot()
+				if value[1] != value[0]*2 {
+					t.Errorf("snapshot value is inconsistent: %v", value)
+					return
+				}
+				if stabilizationNum < lastStabilizationNum {
+					t.Errorf("snapshot stabilization number went backwards: %d < %d", stabilizationNum, lastStabilizationNum)
+					return
+				}
+				lastStabilizationNum = stabilizationNum
+				runtime.Gosched()
+			}
+		}()
+	}
+	started.Wait()
+	for x := 1; x <= 100; x++ {
+		v.Set(x)
+		err := g.ParallelStabilize(ctx)
+		testutil.NoError(t, err)
+	}
+	close(done)
+	readers.Wait()
+
+	value, stabilizationNum := o.Snapshot()
+	testutil.Equal(t, [2]int{100, 200}, value)
+	testutil.Equal(t, 100, stabilizationNum)
+}
+
+func Test_Observe_IsStale(t *testing.T) {
+	ctx := testContext()
+	g := New()
+	v0 := Var(g, "foo")
+	v1 := Var(g, "bar")
+	o0 := MustObserve(g, Map(g, Map(g, v0, ident), ident))
+	o1 := MustObserve(g, Map(g, v1, ident))
+
+	testutil.Equal(t, true, o0.IsStale())
+	testutil.Equal(t, true, o1.IsStale())
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, false, o0.IsStale())
+	testutil.Equal(t, false, o1.IsStale())
+
+	v0.Set("not-foo")
+	testutil.Equal(t, true, o0.IsStale())
+	testutil.Equal(t, false, o1.IsStale())
+
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, false, o0.IsStale())
+}
+
+func Test_Observe_IsStale_always(t *testing.T) {
+	ctx := testContext()
+	g := New()
+	v := Var(g, "foo")
+	a := Always(g, v)
+	o := MustObserve(g, Map(g, a, ident))
+	testutil.Equal(t, true, o.IsStale())
+
+	for x := 0; x < 3; x++ {
+		err := g.Stabilize(ctx)
+		testutil.NoError(t, err)
+		testutil.Equal(t, false, o.IsStale(), "an always ancestor should not make the observer stale")
+	}
+
+	v.Set("bar")
+	testutil.Equal(t, true, o.IsStale())
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, false, o.IsStale())
+
+	g.SetStale(a)
+	testutil.Equal(t, true, o.IsStale())
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, false, o.IsStale())
+}
+
+func Test_Observe_IsStale_sentinel(t *testing.T) {
+	ctx := testContext()
+	g := New()
+	v := Var(g, "foo")
+	m := Map(g, v, ident)
+	_ = Sentinel(g, func() bool { return false }, m)
+	o := MustObserve(g, m)
+
+	for x := 0; x < 3; x++ {
+		err := g.Stabilize(ctx)
+		testutil.NoError(t, err)
+		testutil.Equal(t, false, o.IsStale(), "a sentinel should not make the observer stale")
+	}
+}
+
+func Test_Observe_IsStale_setDuringStabilization(t *testing.T) {
+	ctx := testContext()
+	g := New(OptGraphConcurrent(true))
+	v := Var(g, "foo")
+	o := MustObserve(g, Map(g, v, ident))
+
+	var staleInHandler bool
+	o.OnUpdate(func(_ context.Context, value string) {
+		if value == "foo" {
+			v.Set("bar")
+			staleInHandler = o.IsStale()
+		}
+	})
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, true, staleInHandler, "a staged set should make the observer stale")
+	testutil.Equal(t, true, o.IsStale())
+
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "bar", o.Value())
+	testutil.Equal(t, false, o.IsStale())
+}
+
+func Test_Observe_LastChangedAt_LastStabilizedAt(t *testing.T) {
+	ctx := testContext()
+	g := New()
+	v := Var(g, "foo")
+	m := Map(g, v, ident)
+	o0 := MustObserve(g, m)
+
+	changedAt, changedTime := o0.LastChangedAt()
+	testutil.Equal(t, 0, changedAt)
+	testutil.Equal(t, true, changedTime.IsZero())
+	stabilizedAt, stabilizedTime := o0.LastStabilizedAt()
+	testutil.Equal(t, 0, stabilizedAt)
+	testutil.Equal(t, true, stabilizedTime.IsZero())
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	changedAt, changedTime = o0.LastChangedAt()
+	testutil.Equal(t, 1, changedAt)
+	testutil.Equal(t, false, changedTime.IsZero())
+	stabilizedAt, stabilizedTime = o0.LastStabilizedAt()
+	testutil.Equal(t, 1, stabilizedAt)
+	testutil.Equal(t, changedTime, stabilizedTime)
+
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	changedAt, _ = o0.LastChangedAt()
+	testutil.Equal(t, 1, changedAt)
+	stabilizedAt, stabilizedTime = o0.LastStabilizedAt()
+	testutil.Equal(t, 2, stabilizedAt)
+	testutil.Equal(t, false, stabilizedTime.Before(changedTime))
+
+	o1 := MustObserve(g, m)
+	changedAt, _ = o1.LastChangedAt()
+	testutil.Equal(t, 1, changedAt, "a new observer should see when the node last changed")
+	stabilizedAt, _ = o1.LastStabilizedAt()
+	testutil.Equal(t, 0, stabilizedAt)
+
+	v.Set("bar")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	changedAt, _ = o1.LastChangedAt()
+	testutil.Equal(t, 3, changedAt)
+	stabilizedAt, _ = o1.LastStabilizedAt()
+	testutil.Equal(t, 3, stabilizedAt)
+}
@@ -3,30 +3,43 @@ package incr
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 )
 
-// parallelBatch is an iterator processor that runs in parallel, calling a given delegate for each iterator item seen.
-func parallelBatch[A any](ctx context.Context, fn func(context.Context, A) error, iter func() (A, bool), parallelism int) (err error) {
+// parallelBatch is a list processor that runs in parallel on a worker pool, calling a given delegate for each item.
+//
+// The calling goroutine processes items alongside the pool workers, and each worker takes the next
+// unprocessed item as it finishes the previous item, such that uneven items are balanced across workers.
+func parallelBatch[A any](ctx context.Context, pool *workerPool, fn func(context.Context, A) error, items []A) (err error) {
+	if len(items) == 0 {
+		return
+	}
 	var errOnce sync.Once
-	sem := make(chan A, parallelism)
-	wg := new(sync.WaitGroup)
-
+	next := int64(-1)
 	process := func() {
-		defer wg.Done()
-		workErr := fn(ctx, <-sem)
-		if workErr != nil {
-			errOnce.Do(func() {
-				err = workErr
-			})
+		for {
+			index := atomic.AddInt64(&next, 1)
+			if index >= int64(len(items)) {
+				return
+			}
+			if workErr := fn(ctx, items[index]); workErr != nil {
+				errOnce.Do(func() {
+					err = workErr
+				})
+			}
 		}
 	}
-	w, ok := iter()
-	for ok {
-		sem <- w
-		wg.Add(1)
-		go process()
-		w, ok = iter()
+
+	workers := min(pool.size, len(items))
+	wg := new(sync.WaitGroup)
+	wg.Add(workers - 1)
+	for x := 1; x < workers; x++ {
+		pool.submit(func() {
+			defer wg.Done()
+			process()
+		})
 	}
+	process()
 	wg.Wait()
 	return
 }
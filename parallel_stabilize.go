@@ -2,9 +2,16 @@ package incr
 
 import (
 	"context"
+	"slices"
 	"sync"
+	"time"
 )
 
+// parallelBatchMinCost is the estimated time to recompute a height block
+// below which the block is recomputed on the calling goroutine, as handing
+// the nodes off to the worker pool would take longer than recomputing them.
+const parallelBatchMinCost = 50 * time.Microsecond
+
 // ParallelStabilize stabilizes a graph in parallel.
 //
 // This is done similarly to [Graph.Stabilize], in that nodes are stabilized
@@ -14,7 +21,10 @@ import (
 //
 // Because of the concurrent nature of the block processing, [Graph.ParallelStabilize] is
 // considerably slower to process nodes, specifically because locks have to be acquired and shared
-// state managed carefully.
+// state managed carefully. To offset this, height blocks are recomputed on a pool of goroutines
+// kept by the graph, and blocks that are estimated to be cheap to recompute based on the nodes
+// recomputed previously are recomputed on the calling goroutine. How each node is recomputed can
+// be set with [Node.SetCostHint] and [Node.SetExecutor].
 //
 // You should only reach for [Graph.ParallelStabilize] if you have very long running node recomputations
 // that would benefit from processing in parallel, e.g. if you have nodes that are I/O bound or CPU intensive.
@@ -34,11 +44,14 @@ func (graph *Graph) parallelStabilize(ctx context.Context) (err error) {
 	if graph.recomputeHeap.len() == 0 {
 		return
 	}
+	graph.checkSentinels(ctx)
 
 	var immediateRecompute []INode
 	var immediateRecomputeMu sync.Mutex
 	parallelRecomputeNode := func(ctx context.Context, n INode) (err error) {
-		err = graph.recompute(ctx, n, true)
+		executeNode(n, func() {
+			err = graph.recompute(ctx, n, true)
+		})
 		if n.Node().always {
 			immediateRecomputeMu.Lock()
 			immediateRecompute = append(immediateRecompute, n)
@@ -47,23 +60,106 @@ func (graph *Graph) parallelStabilize(ctx context.Context) (err error) {
 		return
 	}
 
+	// fold the children added concurrently in case we stop on an error.
+	defer graph.recomputeHeap.foldPending()
+
 	var iter recomputeHeapListIter
 	for graph.recomputeHeap.len() > 0 {
 		graph.recomputeHeap.removeMinHeightIter(&iter)
-		err = parallelBatch[INode](ctx, parallelRecomputeNode, iter.Next, graph.parallelism)
+		batch := graph.parallelBatchBuffer[:0]
+		for n, ok := iter.Next(); ok; n, ok = iter.Next() {
+			batch = append(batch, n)
+		}
+		err = graph.parallelStabilizeBatch(ctx, parallelRecomputeNode, batch)
+		clear(batch)
+		graph.parallelBatchBuffer = batch[:0]
 		if err != nil {
 			break
 		}
 	}
 
 	if len(immediateRecompute) > 0 {
-		graph.recomputeHeap.mu.Lock()
 		for _, n := range immediateRecompute {
-			if n.Node().heightInRecomputeHeap == HeightUnset {
-				graph.recomputeHeap.addNodeUnsafe(n)
-			}
+			graph.recomputeHeap.addIfNotPresent(n)
+		}
+	}
+	return
+}
+
+// parallelStabilizeBatch recomputes a height block.
+//
+// Nodes with an executor or with [CostHintExpensive] are recomputed concurrently on the graph
+// worker pool, and nodes with [CostHintCheap] are recomputed on the calling goroutine. The remaining
+// nodes are recomputed on the calling goroutine if they're estimated to be cheap to recompute
+// based on the nodes recomputed previously, and concurrently otherwise.
+func (graph *Graph) parallelStabilizeBatch(ctx context.Context, fn func(context.Context, INode) error, batch []INode) (err error) {
+	var numDefault int
+	for _, n := range batch {
+		if n.Node().costHint == CostHintDefault && n.Node().executor == nil {
+			numDefault++
+		}
+	}
+	inlineDefault := numDefault <= 1 || (graph.parallelNodeCost > 0 && time.Duration(numDefault)*graph.parallelNodeCost < parallelBatchMinCost)
+
+	// move the nodes that are recomputed concurrently to the front of the block.
+	var numConcurrent int
+	for index, n := range batch {
+		if parallelStabilizeConcurrently(n, inlineDefault) {
+			batch[numConcurrent], batch[index] = batch[index], batch[numConcurrent]
+			numConcurrent++
+		}
+	}
+	concurrent, inline := batch[:numConcurrent], batch[numConcurrent:]
+	if graph.deterministic {
+		slices.SortFunc(concurrent, compareHandles)
+		slices.SortFunc(inline, compareHandles)
+	}
+
+	started := time.Now()
+	err = parallelBatch(ctx, graph.workerPool, fn, concurrent)
+	for _, n := range inline {
+		if nodeErr := fn(ctx, n); nodeErr != nil && err == nil {
+			err = nodeErr
 		}
-		graph.recomputeHeap.mu.Unlock()
+	}
+
+	// estimate the per-node cost as the time each worker was busy divided by
+	// the nodes in the block, smoothing over previous blocks; blocks with
+	// hinted nodes aren't representative of the default nodes and are skipped.
+	if numDefault < len(batch) {
+		return
+	}
+	workers := max(min(graph.workerPool.size, len(concurrent)), 1)
+	sample := time.Since(started) * time.Duration(workers) / time.Duration(len(batch))
+	if graph.parallelNodeCost == 0 {
+		graph.parallelNodeCost = sample
+	} else {
+		graph.parallelNodeCost += (sample - graph.parallelNodeCost) / 8
 	}
 	return
 }
+
+func parallelStabilizeConcurrently(n INode, inlineDefault bool) bool {
+	nn := n.Node()
+	if nn.executor != nil {
+		return true
+	}
+	switch nn.costHint {
+	case CostHintCheap:
+		return false
+	case CostHintExpensive:
+		return true
+	default:
+		return !inlineDefault
+	}
+}
+
+// executeNode calls a given function for a node on the
+// node's executor, or directly if the node has no executor.
+func executeNode(n INode, fn func()) {
+	if executor := n.Node().executor; executor != nil {
+		executor.Execute(fn)
+		return
+	}
+	fn()
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -326,3 +327,99 @@ func Test_ParallelStabilize_alwaysInRecomputeHeapOnError(t *testing.T) {
 	testutil.Error(t, err)
 	testutil.Equal(t, "this is only a test", err.Error())
 }
+
+func Test_ParallelStabilize_costHintExpensive(t *testing.T) {
+	ctx := testContext()
+	g := New(OptGraphParallelism(2))
+
+	// the two nodes wait for each other to start,
+	// which requires they run concurrently.
+	started := make(chan struct{}, 2)
+	waitForOther := func(v string) (string, error) {
+		started <- struct{}{}
+		deadline := time.After(5 * time.Second)
+		for len(started) < 2 {
+			select {
+			case <-deadline:
+				return "", fmt.Errorf("timed out waiting for other node")
+			case <-time.After(time.Millisecond):
+			}
+		}
+		return v, nil
+	}
+	v := Var(g, "foo")
+	m0 := MapContext(g, v, func(_ context.Context, v string) (string, error) { return waitForOther(v) })
+	m0.Node().SetCostHint(CostHintExpensive)
+	m1 := MapContext(g, v, func(_ context.Context, v string) (string, error) { return waitForOther(v) })
+	m1.Node().SetCostHint(CostHintExpensive)
+	o := MustObserve(g, Map2(g, m0, m1, concat))
+
+	// make the graph think nodes are very cheap.
+	g.parallelNodeCost = time.Nanosecond
+
+	err := g.ParallelStabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "foofoo", o.Value())
+}
+
+func Test_ParallelStabilize_costHintCheap(t *testing.T) {
+	ctx := testContext()
+	g := New(OptGraphParallelism(4))
+
+	var inFlight, maxInFlight int32
+	cheap := func(v string) string {
+		current := atomic.AddInt32(&inFlight, 1)
+		if current > atomic.LoadInt32(&maxInFlight) {
+			atomic.StoreInt32(&maxInFlight, current)
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		return v
+	}
+	v := Var(g, "foo")
+	var nodes []Incr[string]
+	for x := 0; x < 4; x++ {
+		m := Map(g, v, cheap)
+		m.Node().SetCostHint(CostHintCheap)
+		nodes = append(nodes, m)
+	}
+	_ = MustObserve(g, MapN(g, identMany[string], nodes...))
+
+	err := g.ParallelStabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 1, maxInFlight)
+}
+
+func Test_ParallelStabilize_executor(t *testing.T) {
+	ctx := testContext()
+	g := New(OptGraphParallelism(4))
+
+	executor := NewDedicatedExecutor(1)
+	defer executor.Close()
+	var executed int32
+	countingExecutor := ExecutorFunc(func(fn func()) {
+		executor.Execute(func() {
+			atomic.AddInt32(&executed, 1)
+			fn()
+		})
+	})
+
+	v := Var(g, "foo")
+	m0 := Map(g, v, ident)
+	m0.Node().SetExecutor(countingExecutor)
+	m1 := Map(g, v, ident)
+	m1.Node().SetExecutor(countingExecutor)
+	m2 := Map(g, v, ident)
+	o := MustObserve(g, MapN(g, identMany[string], m0, m1, m2))
+
+	err := g.ParallelStabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 2, executed)
+	testutil.Equal(t, "foo", o.Value())
+
+	v.Set("bar")
+	err = g.DataflowStabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 4, executed)
+	testutil.Equal(t, "bar", o.Value())
+}
@@ -0,0 +1,87 @@
+package incr
+
+import "context"
+
+// Peek returns the current value of a node, stabilizing the nodes it depends on first.
+//
+// If the node is not necessary, that is it's not observed, Peek makes the node necessary
+// for the duration of the call, and as a result reading the value of a node that is not
+// observed with Peek returns the same value the node would have if it were observed.
+//
+// Only the node and its ancestors are recomputed; other nodes that need to be
+// recomputed are left for the next stabilization. Update handlers of the nodes
+// that are recomputed are called as they would be by [Graph.Stabilize].
+//
+// Peek stabilizes the graph, and as a result returns [ErrAlreadyStabilizing] if
+// the graph is stabilizing and is not concurrent.
+func Peek[A any](ctx context.Context, n Incr[A]) (value A, err error) {
+	graph := GraphForNode(n)
+	if err = graph.ensureNotStabilizing(ctx); err != nil {
+		return
+	}
+	ctx = graph.stabilizeStart(ctx)
+	defer func() {
+		graph.stabilizeEnd(ctx, err)
+	}()
+
+	nn := n.Node()
+	if !nn.isNecessary() {
+		nn.forceNecessary = true
+		defer func() {
+			nn.forceNecessary = false
+			graph.checkIfUnnecessary(n)
+		}()
+		if err = graph.becameNecessary(n); err != nil {
+			return
+		}
+	}
+	if err = graph.stabilizeCone(ctx, n); err != nil {
+		return
+	}
+	value = n.Value()
+	return
+}
+
+// stabilizeCone recomputes the nodes in the recompute heap that a given node depends on,
+// including the node itself, leaving the other nodes in the recompute heap.
+func (graph *Graph) stabilizeCone(ctx context.Context, n INode) (err error) {
+	cone := ancestorCone(n)
+	var deferred []INode
+	var next INode
+	for graph.recomputeHeap.numItems > 0 {
+		next, _ = graph.recomputeHeap.removeMinUnsafe()
+		if _, ok := cone[next.Node()]; !ok {
+			deferred = append(deferred, next)
+			continue
+		}
+		err = graph.recompute(ctx, next, false /*parallel*/)
+		if next.Node().always {
+			deferred = append(deferred, next)
+		}
+		if err != nil {
+			break
+		}
+		// bind changes can add ancestors to the cone.
+		if _, isBindChange := next.(IBindChange); isBindChange && next.Node().changedAt == graph.stabilizationNum {
+			cone = ancestorCone(n)
+		}
+	}
+	graph.recomputeHeap.addIfNotPresent(deferred...)
+	return
+}
+
+// ancestorCone returns the set of a node and its ancestors.
+func ancestorCone(n INode) map[*Node]struct{} {
+	cone := make(map[*Node]struct{})
+	queue := []INode{n}
+	for len(queue) > 0 {
+		next := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		if _, ok := cone[next.Node()]; ok {
+			continue
+		}
+		cone[next.Node()] = struct{}{}
+		queue = append(queue, next.Node().parents...)
+	}
+	return cone
+}
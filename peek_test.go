@@ -0,0 +1,121 @@
+package incr
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/wcharczuk/go-incr/testutil"
+)
+
+func Test_Peek(t *testing.T) {
+	ctx := testContext()
+	g := New()
+	v := Var(g, "foo")
+	m0 := Map(g, v, func(vv string) string { return vv + "-0" })
+	m1 := Map(g, m0, func(vv string) string { return vv + "-1" })
+
+	testutil.Equal(t, "", m1.Value())
+
+	value, err := Peek(ctx, m1)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "foo-0-1", value)
+	testutil.Equal(t, false, g.Has(m1), "peek should release the node")
+	testutil.Equal(t, false, g.Has(m0), "peek should release the node's ancestors")
+	testutil.Equal(t, false, m1.Node().isNecessary())
+
+	v.Set("bar")
+	value, err = Peek(ctx, m1)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "bar-0-1", value)
+}
+
+func Test_Peek_onlyRecomputesCone(t *testing.T) {
+	ctx := testContext()
+	g := New()
+	v0 := Var(g, "foo")
+	v1 := Var(g, "bar")
+	m0 := Map(g, v0, ident)
+	m1 := Map(g, v1, ident)
+	o0 := MustObserve(g, m0)
+	o1 := MustObserve(g, m1)
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+
+	v0.Set("not-foo")
+	v1.Set("not-bar")
+	value, err := Peek(ctx, m0)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "not-foo", value)
+	testutil.Equal(t, "not-foo", o0.Value())
+	testutil.Equal(t, "bar", o1.Value())
+	testutil.Equal(t, false, o0.IsStale())
+	testutil.Equal(t, true, o1.IsStale())
+
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "not-bar", o1.Value())
+}
+
+func Test_Peek_bind(t *testing.T) {
+	ctx := testContext()
+	g := New()
+	v := Var(g, "a")
+	a := Map(g, Return(g, "a-value"), ident)
+	b := Map(g, Return(g, "b-value"), ident)
+	bind := Bind(g, v, func(_ Scope, vv string) Incr[string] {
+		if vv == "a" {
+			return a
+		}
+		return b
+	})
+	m := Map(g, bind, func(vv string) string { return vv + "!" })
+
+	value, err := Peek(ctx, m)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "a-value!", value)
+
+	v.Set("b")
+	value, err = Peek(ctx, m)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "b-value!", value)
+}
+
+func Test_Peek_error(t *testing.T) {
+	ctx := testContext()
+	g := New()
+	v := Var(g, "foo")
+	m := MapContext(g, v, func(_ context.Context, _ string) (string, error) {
+		return "", fmt.Errorf("this is only a test")
+	})
+
+	_, err := Peek(ctx, m)
+	testutil.Error(t, err)
+	testutil.Equal(t, false, g.Has(m))
+}
+
+func Test_OptGraphOnUnnecessaryRead(t *testing.T) {
+	ctx := testContext()
+	var reads []INode
+	g := New(OptGraphOnUnnecessaryRead(func(n INode) {
+		reads = append(reads, n)
+	}))
+	v := Var(g, "foo")
+	m := Map(g, v, ident)
+
+	_ = v.Value()
+	testutil.Empty(t, reads, "var reads should not be checked")
+
+	_ = m.Value()
+	testutil.Equal(t, []INode{m}, reads)
+
+	_, err := Peek(ctx, m)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 1, len(reads), "peek should not read the node while it's unnecessary")
+
+	_ = MustObserve(g, m)
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	_ = m.Value()
+	testutil.Equal(t, 1, len(reads))
+}
@@ -2,26 +2,44 @@ package incr
 
 import (
 	"fmt"
+	"math"
 	"sync"
+	"sync/atomic"
 )
 
+// newRecomputeHeap returns a new recompute heap, preallocating
+// height blocks for a given max height; blocks above the preallocated
+// heights are added as they're needed.
 func newRecomputeHeap(maxHeight int) *recomputeHeap {
 	return &recomputeHeap{
-		heights: make([]*recomputeHeapList, maxHeight),
+		heights:          make([]*recomputeHeapList, initialHeightsSize(maxHeight)),
+		pendingMinHeight: math.MaxInt64,
+		pendingMaxHeight: -1,
 	}
 }
 
+// recomputeHeap is a heap of nodes to recompute, with a list of nodes for each height.
+//
+// Most operations hold mu exclusively, but nodes can also be added while holding mu shared
+// with [recomputeHeap.addIfStaleConcurrent], in which case only the list for the node's
+// height is locked. Nodes added this way are counted in the pending fields, which are folded
+// into numItems, minHeight and maxHeight by the next operation that holds mu exclusively.
 type recomputeHeap struct {
-	mu        sync.Mutex
+	mu        sync.RWMutex
 	minHeight int
 	maxHeight int
 	heights   []*recomputeHeapList
 	numItems  int
+
+	pendingItems     int64
+	pendingMinHeight int64
+	pendingMaxHeight int64
 }
 
 func (rh *recomputeHeap) clear() {
 	rh.mu.Lock()
 	defer rh.mu.Unlock()
+	rh.foldPendingUnsafe()
 
 	rh.heights = make([]*recomputeHeapList, len(rh.heights))
 	rh.minHeight = 0
@@ -30,41 +48,88 @@ func (rh *recomputeHeap) clear() {
 }
 
 func (rh *recomputeHeap) len() int {
-	rh.mu.Lock()
-	defer rh.mu.Unlock()
-	return rh.numItems
+	rh.mu.RLock()
+	defer rh.mu.RUnlock()
+	return rh.numItems + int(atomic.LoadInt64(&rh.pendingItems))
+}
+
+// addIfStaleConcurrent adds a node to the heap if it's necessary, stale
+// and not already in the heap, and can be called concurrently.
+//
+// Only the list for the node's height is locked, unless the
+// heap has to add the list, in which case the heap is locked.
+func (rh *recomputeHeap) addIfStaleConcurrent(n INode) {
+	rh.mu.RLock()
+	height := n.Node().height
+	if height >= len(rh.heights) || rh.heights[height] == nil {
+		rh.mu.RUnlock()
+		rh.mu.Lock()
+		defer rh.mu.Unlock()
+		rh.foldPendingUnsafe()
+		if n.Node().isNecessary() && n.Node().isStale() && n.Node().heightInRecomputeHeap == HeightUnset {
+			rh.addNodeUnsafe(n)
+		}
+		return
+	}
+	defer rh.mu.RUnlock()
+
+	heightBlock := rh.heights[height]
+	heightBlock.mu.Lock()
+	defer heightBlock.mu.Unlock()
+	if !n.Node().isNecessary() || !n.Node().isStale() || n.Node().heightInRecomputeHeap != HeightUnset {
+		return
+	}
+	n.Node().heightInRecomputeHeap = height
+	heightBlock.push(n)
+	atomic.AddInt64(&rh.pendingItems, 1)
+	for pendingMin := atomic.LoadInt64(&rh.pendingMinHeight); int64(height) < pendingMin; pendingMin = atomic.LoadInt64(&rh.pendingMinHeight) {
+		if atomic.CompareAndSwapInt64(&rh.pendingMinHeight, pendingMin, int64(height)) {
+			break
+		}
+	}
+	for pendingMax := atomic.LoadInt64(&rh.pendingMaxHeight); int64(height) > pendingMax; pendingMax = atomic.LoadInt64(&rh.pendingMaxHeight) {
+		if atomic.CompareAndSwapInt64(&rh.pendingMaxHeight, pendingMax, int64(height)) {
+			break
+		}
+	}
 }
 
 func (rh *recomputeHeap) add(nodes ...INode) {
 	rh.mu.Lock()
 	defer rh.mu.Unlock()
+	rh.foldPendingUnsafe()
 
 	for _, n := range nodes {
 		rh.addNodeUnsafe(n)
 	}
 }
 
-func (rh *recomputeHeap) addIfNotPresent(n INode) {
+func (rh *recomputeHeap) addIfNotPresent(nodes ...INode) {
 	rh.mu.Lock()
 	defer rh.mu.Unlock()
-	if n.Node().heightInRecomputeHeap == HeightUnset {
-		rh.addNodeUnsafe(n)
+	rh.foldPendingUnsafe()
+	for _, n := range nodes {
+		if n.Node().heightInRecomputeHeap == HeightUnset {
+			rh.addNodeUnsafe(n)
+		}
 	}
 }
 
 func (rh *recomputeHeap) fix(n INode) {
 	rh.mu.Lock()
 	defer rh.mu.Unlock()
+	rh.foldPendingUnsafe()
 	rh.fixUnsafe(n)
 }
 
 func (rh *recomputeHeap) has(s INode) (ok bool) {
 	rh.mu.Lock()
 	defer rh.mu.Unlock()
+	rh.foldPendingUnsafe()
 
-	nodeID := s.Node().id
+	sn := s.Node()
 	for x := rh.minHeight; x <= rh.maxHeight; x++ {
-		if rh.heights[x].has(nodeID) {
+		if rh.heights[x].has(sn) {
 			ok = true
 			return
 		}
@@ -91,6 +156,7 @@ func (i *recomputeHeapListIter) Next() (INode, bool) {
 func (rh *recomputeHeap) removeMinHeightIter(iter *recomputeHeapListIter) {
 	rh.mu.Lock()
 	defer rh.mu.Unlock()
+	rh.foldPendingUnsafe()
 
 	var heightBlock *recomputeHeapList
 	for x := 0; x < len(rh.heights); x++ {
@@ -107,9 +173,37 @@ func (rh *recomputeHeap) removeMinHeightIter(iter *recomputeHeapListIter) {
 	rh.minHeight = rh.nextMinHeightUnsafe()
 }
 
+// removeAll removes all the nodes from the heap, returning them
+// in ascending height order.
+func (rh *recomputeHeap) removeAll() (output []INode) {
+	rh.mu.Lock()
+	defer rh.mu.Unlock()
+	rh.foldPendingUnsafe()
+
+	output = make([]INode, 0, rh.numItems)
+	for x := rh.minHeight; x < len(rh.heights) && rh.numItems > 0; x++ {
+		heightBlock := rh.heights[x]
+		if heightBlock == nil || heightBlock.len() == 0 {
+			continue
+		}
+		iter := recomputeHeapListIter{cursor: heightBlock.head}
+		heightBlock.head = nil
+		heightBlock.tail = nil
+		rh.numItems = rh.numItems - len(heightBlock.items)
+		clear(heightBlock.items)
+		for n, ok := iter.Next(); ok; n, ok = iter.Next() {
+			output = append(output, n)
+		}
+	}
+	rh.minHeight = 0
+	rh.maxHeight = 0
+	return
+}
+
 func (rh *recomputeHeap) remove(node INode) {
 	rh.mu.Lock()
 	defer rh.mu.Unlock()
+	rh.foldPendingUnsafe()
 	rh.removeNodeUnsafe(node)
 }
 
@@ -117,6 +211,33 @@ func (rh *recomputeHeap) remove(node INode) {
 // utils
 //
 
+func (rh *recomputeHeap) foldPending() {
+	rh.mu.Lock()
+	defer rh.mu.Unlock()
+	rh.foldPendingUnsafe()
+}
+
+// foldPendingUnsafe folds the nodes added concurrently into the heap counts and bounds.
+func (rh *recomputeHeap) foldPendingUnsafe() {
+	pendingItems := atomic.LoadInt64(&rh.pendingItems)
+	if pendingItems == 0 {
+		return
+	}
+	pendingMinHeight := int(atomic.LoadInt64(&rh.pendingMinHeight))
+	pendingMaxHeight := int(atomic.LoadInt64(&rh.pendingMaxHeight))
+	if rh.numItems == 0 {
+		rh.minHeight = pendingMinHeight
+		rh.maxHeight = pendingMaxHeight
+	} else {
+		rh.minHeight = min(rh.minHeight, pendingMinHeight)
+		rh.maxHeight = max(rh.maxHeight, pendingMaxHeight)
+	}
+	rh.numItems += int(pendingItems)
+	atomic.StoreInt64(&rh.pendingItems, 0)
+	atomic.StoreInt64(&rh.pendingMinHeight, math.MaxInt64)
+	atomic.StoreInt64(&rh.pendingMaxHeight, -1)
+}
+
 func (rh *recomputeHeap) removeMinUnsafe() (node INode, ok bool) {
 	for x := rh.minHeight; x <= rh.maxHeight; x++ {
 		if rh.heights[x] != nil && rh.heights[x].len() > 0 {
@@ -149,9 +270,8 @@ func (rh *recomputeHeap) addNodeUnsafe(s INode) {
 
 func (rh *recomputeHeap) removeNodeUnsafe(item INode) {
 	rh.numItems--
-	id := item.Node().id
 	height := item.Node().heightInRecomputeHeap
-	rh.heights[height].remove(id)
+	rh.heights[height].remove(item.Node())
 	isLastAtHeight := rh.heights[height].len() == 0
 	if height == rh.minHeight && isLastAtHeight {
 		rh.minHeight = rh.nextMinHeightUnsafe()
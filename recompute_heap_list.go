@@ -1,15 +1,19 @@
 package incr
 
+import "sync"
+
 // recomputeHeapList is a linked recomputeHeapList structure that can be used
 // as a ordered recomputeHeapList as well as a constant time
 // map using a similar technique to high throughput LRU queues.
 type recomputeHeapList struct {
+	// mu is held when nodes are added to the list concurrently
+	mu sync.Mutex
 	// head is the "first" element in the list
 	head INode
 	// tail is the "last" element in the list
 	tail INode
 	// items is a map between the key and the actual list item(s)
-	items map[Identifier]INode
+	items map[*Node]INode
 }
 
 // func (l *recomputeHeapList) isEmpty() bool {
@@ -25,13 +29,13 @@ func (l *recomputeHeapList) len() int {
 
 func (l *recomputeHeapList) push(v INode) {
 	if l.items == nil {
-		l.items = make(map[Identifier]INode)
+		l.items = make(map[*Node]INode)
 	}
 
 	v.Node().nextInRecomputeHeap = nil
 	v.Node().previousInRecomputeHeap = nil
 
-	l.items[v.Node().id] = v
+	l.items[v.Node()] = v
 	if l.head == nil {
 		l.head = v
 		l.tail = v
@@ -43,12 +47,12 @@ func (l *recomputeHeapList) push(v INode) {
 	l.tail = v
 }
 
-func (l *recomputeHeapList) pop() (k Identifier, v INode, ok bool) {
+func (l *recomputeHeapList) pop() (k *Node, v INode, ok bool) {
 	if l.head == nil {
 		return
 	}
 
-	k = l.head.Node().id
+	k = l.head.Node()
 	v = l.head
 	ok = true
 	delete(l.items, k)
@@ -70,21 +74,25 @@ func (l *recomputeHeapList) pop() (k Identifier, v INode, ok bool) {
 	return
 }
 
-func (l *recomputeHeapList) consume(fn func(Identifier, INode)) {
+// consume calls a given function for each item in the list
+// in the order the items were pushed, emptying the list.
+func (l *recomputeHeapList) consume(fn func(*Node, INode)) {
 	if l.items == nil {
 		return
 	}
-	for key, value := range l.items {
+	for cursor := l.head; cursor != nil; {
+		value := cursor
+		cursor = value.Node().nextInRecomputeHeap
 		value.Node().nextInRecomputeHeap = nil
 		value.Node().previousInRecomputeHeap = nil
-		fn(key, value)
+		fn(value.Node(), value)
 	}
 	l.head = nil
 	l.tail = nil
 	clear(l.items)
 }
 
-func (l *recomputeHeapList) has(k Identifier) (ok bool) {
+func (l *recomputeHeapList) has(k *Node) (ok bool) {
 	if l == nil || l.items == nil {
 		return
 	}
@@ -92,7 +100,7 @@ func (l *recomputeHeapList) has(k Identifier) (ok bool) {
 	return
 }
 
-func (l *recomputeHeapList) remove(k Identifier) (ok bool) {
+func (l *recomputeHeapList) remove(k *Node) (ok bool) {
 	if len(l.items) == 0 {
 		return
 	}
@@ -10,7 +10,7 @@ func nodePtrID(n INode) Identifier {
 	if n == nil {
 		return zero
 	}
-	return n.Node().id
+	return n.Node().ID()
 }
 
 func rhnext(n INode) INode {
@@ -36,7 +36,6 @@ func Test_recomputeHeapList_push_pop(t *testing.T) {
 	n2 := newHeightIncr(g, 0)
 	n3 := newHeightIncr(g, 0)
 
-	var zeroID Identifier
 	id, n, ok := q.pop()
 
 	// Region: empty list
@@ -44,14 +43,14 @@ func Test_recomputeHeapList_push_pop(t *testing.T) {
 		testutil.Equal(t, false, ok)
 		testutil.Nil(t, q.head)
 		testutil.Nil(t, q.tail)
-		testutil.Equal(t, zeroID, id)
+		testutil.Nil(t, id)
 		testutil.Nil(t, n)
 		testutil.Equal(t, 0, q.len())
 
-		testutil.Equal(t, false, q.has(n0.Node().id))
-		testutil.Equal(t, false, q.has(n1.Node().id))
-		testutil.Equal(t, false, q.has(n2.Node().id))
-		testutil.Equal(t, false, q.has(n3.Node().id))
+		testutil.Equal(t, false, q.has(n0.Node()))
+		testutil.Equal(t, false, q.has(n1.Node()))
+		testutil.Equal(t, false, q.has(n2.Node()))
+		testutil.Equal(t, false, q.has(n3.Node()))
 	}
 
 	// Region: push 0
@@ -63,17 +62,17 @@ func Test_recomputeHeapList_push_pop(t *testing.T) {
 		testutil.Nil(t, rhprev(q.tail))
 		testutil.Equal(t, q.head, q.tail)
 		testutil.Equal(t, 1, q.len())
-		testutil.Equal(t, true, q.has(n0.Node().id))
-		testutil.Equal(t, false, q.has(n1.Node().id))
-		testutil.Equal(t, false, q.has(n2.Node().id))
-		testutil.Equal(t, false, q.has(n3.Node().id))
-		testutil.Equal(t, n0.n.id, nodePtrID(q.head))
-		testutil.Equal(t, n0.n.id, nodePtrID(q.tail))
-
-		testutil.Equal(t, true, q.has(n0.Node().id))
-		testutil.Equal(t, false, q.has(n1.Node().id))
-		testutil.Equal(t, false, q.has(n2.Node().id))
-		testutil.Equal(t, false, q.has(n3.Node().id))
+		testutil.Equal(t, true, q.has(n0.Node()))
+		testutil.Equal(t, false, q.has(n1.Node()))
+		testutil.Equal(t, false, q.has(n2.Node()))
+		testutil.Equal(t, false, q.has(n3.Node()))
+		testutil.Equal(t, n0.n.ID(), nodePtrID(q.head))
+		testutil.Equal(t, n0.n.ID(), nodePtrID(q.tail))
+
+		testutil.Equal(t, true, q.has(n0.Node()))
+		testutil.Equal(t, false, q.has(n1.Node()))
+		testutil.Equal(t, false, q.has(n2.Node()))
+		testutil.Equal(t, false, q.has(n3.Node()))
 	}
 
 	// Region: push 1
@@ -91,17 +90,17 @@ func Test_recomputeHeapList_push_pop(t *testing.T) {
 		testutil.Equal(t, rhnext(q.head), q.tail)
 		testutil.Equal(t, rhprev(q.tail), q.head)
 		testutil.Equal(t, 2, q.len())
-		testutil.Equal(t, n0.Node().id, nodePtrID(q.head))
-		testutil.Equal(t, true, q.has(n0.Node().id))
-		testutil.Equal(t, true, q.has(n1.Node().id))
-		testutil.Equal(t, false, q.has(n2.Node().id))
-		testutil.Equal(t, false, q.has(n3.Node().id))
-		testutil.Equal(t, n1.Node().id, nodePtrID(q.tail))
-
-		testutil.Equal(t, true, q.has(n0.Node().id))
-		testutil.Equal(t, true, q.has(n1.Node().id))
-		testutil.Equal(t, false, q.has(n2.Node().id))
-		testutil.Equal(t, false, q.has(n3.Node().id))
+		testutil.Equal(t, n0.Node().ID(), nodePtrID(q.head))
+		testutil.Equal(t, true, q.has(n0.Node()))
+		testutil.Equal(t, true, q.has(n1.Node()))
+		testutil.Equal(t, false, q.has(n2.Node()))
+		testutil.Equal(t, false, q.has(n3.Node()))
+		testutil.Equal(t, n1.Node().ID(), nodePtrID(q.tail))
+
+		testutil.Equal(t, true, q.has(n0.Node()))
+		testutil.Equal(t, true, q.has(n1.Node()))
+		testutil.Equal(t, false, q.has(n2.Node()))
+		testutil.Equal(t, false, q.has(n3.Node()))
 	}
 
 	// Region: push 2
@@ -121,17 +120,17 @@ func Test_recomputeHeapList_push_pop(t *testing.T) {
 		testutil.Equal(t, rhprev(rhprev(q.tail)), q.head)
 		testutil.NotEqual(t, q.head, q.tail)
 		testutil.Equal(t, 3, q.len())
-		testutil.Equal(t, true, q.has(n0.Node().id))
-		testutil.Equal(t, true, q.has(n1.Node().id))
-		testutil.Equal(t, true, q.has(n2.Node().id))
-		testutil.Equal(t, false, q.has(n3.Node().id))
-		testutil.Equal(t, n0.Node().id, nodePtrID(q.head))
-		testutil.Equal(t, n2.Node().id, nodePtrID(q.tail))
-
-		testutil.Equal(t, true, q.has(n0.Node().id))
-		testutil.Equal(t, true, q.has(n1.Node().id))
-		testutil.Equal(t, true, q.has(n2.Node().id))
-		testutil.Equal(t, false, q.has(n3.Node().id))
+		testutil.Equal(t, true, q.has(n0.Node()))
+		testutil.Equal(t, true, q.has(n1.Node()))
+		testutil.Equal(t, true, q.has(n2.Node()))
+		testutil.Equal(t, false, q.has(n3.Node()))
+		testutil.Equal(t, n0.Node().ID(), nodePtrID(q.head))
+		testutil.Equal(t, n2.Node().ID(), nodePtrID(q.tail))
+
+		testutil.Equal(t, true, q.has(n0.Node()))
+		testutil.Equal(t, true, q.has(n1.Node()))
+		testutil.Equal(t, true, q.has(n2.Node()))
+		testutil.Equal(t, false, q.has(n3.Node()))
 	}
 
 	// Region: push 3
@@ -153,20 +152,20 @@ func Test_recomputeHeapList_push_pop(t *testing.T) {
 		testutil.Equal(t, rhprev(rhprev(rhprev(q.tail))), q.head)
 		testutil.NotEqual(t, q.head, q.tail)
 		testutil.Equal(t, 4, q.len())
-		testutil.Equal(t, true, q.has(n0.Node().id))
-		testutil.Equal(t, true, q.has(n1.Node().id))
-		testutil.Equal(t, true, q.has(n2.Node().id))
-		testutil.Equal(t, true, q.has(n3.Node().id))
-		testutil.Equal(t, n0.Node().id, nodePtrID(q.head))
-		testutil.Equal(t, n3.Node().id, nodePtrID(q.tail))
+		testutil.Equal(t, true, q.has(n0.Node()))
+		testutil.Equal(t, true, q.has(n1.Node()))
+		testutil.Equal(t, true, q.has(n2.Node()))
+		testutil.Equal(t, true, q.has(n3.Node()))
+		testutil.Equal(t, n0.Node().ID(), nodePtrID(q.head))
+		testutil.Equal(t, n3.Node().ID(), nodePtrID(q.tail))
 	}
 
 	// Region: pop 0
 	{
 		id, n, ok = q.pop()
 		testutil.Equal(t, true, ok)
-		testutil.Equal(t, n0.n.id, id)
-		testutil.Equal(t, n0.n.id, n.Node().id)
+		testutil.Equal(t, n0.n.ID(), id.ID())
+		testutil.Equal(t, n0.n.ID(), n.Node().ID())
 		testutil.NotNil(t, q.head)
 		testutil.NotNil(t, rhnext(q.head))
 		testutil.NotNil(t, rhnext(rhnext(q.head)))
@@ -175,12 +174,12 @@ func Test_recomputeHeapList_push_pop(t *testing.T) {
 		testutil.NotNil(t, q.tail)
 		testutil.NotEqual(t, q.head, q.tail)
 		testutil.Equal(t, 3, q.len())
-		testutil.Equal(t, false, q.has(n0.Node().id))
-		testutil.Equal(t, true, q.has(n1.Node().id))
-		testutil.Equal(t, true, q.has(n2.Node().id))
-		testutil.Equal(t, true, q.has(n3.Node().id))
-		testutil.Equal(t, n1.Node().id, nodePtrID(q.head))
-		testutil.Equal(t, n3.Node().id, nodePtrID(q.tail))
+		testutil.Equal(t, false, q.has(n0.Node()))
+		testutil.Equal(t, true, q.has(n1.Node()))
+		testutil.Equal(t, true, q.has(n2.Node()))
+		testutil.Equal(t, true, q.has(n3.Node()))
+		testutil.Equal(t, n1.Node().ID(), nodePtrID(q.head))
+		testutil.Equal(t, n3.Node().ID(), nodePtrID(q.tail))
 
 		testutil.Nil(t, n.Node().nextInRecomputeHeap)
 		testutil.Nil(t, n.Node().previousInRecomputeHeap)
@@ -190,8 +189,8 @@ func Test_recomputeHeapList_push_pop(t *testing.T) {
 	{
 		id, n, ok = q.pop()
 		testutil.Equal(t, true, ok)
-		testutil.Equal(t, n1.n.id, id)
-		testutil.Equal(t, n1.n.id, n.Node().id)
+		testutil.Equal(t, n1.n.ID(), id.ID())
+		testutil.Equal(t, n1.n.ID(), n.Node().ID())
 		testutil.NotNil(t, q.head)
 		testutil.NotNil(t, rhnext(q.head))
 		testutil.Nil(t, rhnext(rhnext(q.head)))
@@ -202,12 +201,12 @@ func Test_recomputeHeapList_push_pop(t *testing.T) {
 		testutil.Equal(t, rhprev(q.tail), q.head)
 		testutil.NotEqual(t, q.head, q.tail)
 		testutil.Equal(t, 2, q.len())
-		testutil.Equal(t, false, q.has(n0.Node().id))
-		testutil.Equal(t, false, q.has(n1.Node().id))
-		testutil.Equal(t, true, q.has(n2.Node().id))
-		testutil.Equal(t, true, q.has(n3.Node().id))
-		testutil.Equal(t, n2.n.id, nodePtrID(q.head))
-		testutil.Equal(t, n3.n.id, nodePtrID(q.tail))
+		testutil.Equal(t, false, q.has(n0.Node()))
+		testutil.Equal(t, false, q.has(n1.Node()))
+		testutil.Equal(t, true, q.has(n2.Node()))
+		testutil.Equal(t, true, q.has(n3.Node()))
+		testutil.Equal(t, n2.n.ID(), nodePtrID(q.head))
+		testutil.Equal(t, n3.n.ID(), nodePtrID(q.tail))
 
 		testutil.Nil(t, n.Node().nextInRecomputeHeap)
 		testutil.Nil(t, n.Node().previousInRecomputeHeap)
@@ -217,20 +216,20 @@ func Test_recomputeHeapList_push_pop(t *testing.T) {
 	{
 		id, n, ok = q.pop()
 		testutil.Equal(t, true, ok)
-		testutil.Equal(t, n2.n.id, id)
-		testutil.Equal(t, n2.n.id, n.Node().id)
+		testutil.Equal(t, n2.n.ID(), id.ID())
+		testutil.Equal(t, n2.n.ID(), n.Node().ID())
 		testutil.NotNil(t, q.head)
 		testutil.Nil(t, rhnext(q.head))
 		testutil.NotNil(t, q.tail)
 		testutil.Equal(t, q.head, q.tail)
 		testutil.Equal(t, 1, q.len())
-		testutil.Equal(t, n3.n.id, nodePtrID(q.head))
-		testutil.Equal(t, n3.n.id, nodePtrID(q.tail))
+		testutil.Equal(t, n3.n.ID(), nodePtrID(q.head))
+		testutil.Equal(t, n3.n.ID(), nodePtrID(q.tail))
 
-		testutil.Equal(t, false, q.has(n0.Node().id))
-		testutil.Equal(t, false, q.has(n1.Node().id))
-		testutil.Equal(t, false, q.has(n2.Node().id))
-		testutil.Equal(t, true, q.has(n3.Node().id))
+		testutil.Equal(t, false, q.has(n0.Node()))
+		testutil.Equal(t, false, q.has(n1.Node()))
+		testutil.Equal(t, false, q.has(n2.Node()))
+		testutil.Equal(t, true, q.has(n3.Node()))
 
 		testutil.Nil(t, n.Node().nextInRecomputeHeap)
 		testutil.Nil(t, n.Node().previousInRecomputeHeap)
@@ -240,16 +239,16 @@ func Test_recomputeHeapList_push_pop(t *testing.T) {
 	{
 		id, n, ok = q.pop()
 		testutil.Equal(t, true, ok)
-		testutil.Equal(t, n3.n.id, id)
-		testutil.Equal(t, n3.n.id, n.Node().id)
+		testutil.Equal(t, n3.n.ID(), id.ID())
+		testutil.Equal(t, n3.n.ID(), n.Node().ID())
 		testutil.Nil(t, q.head)
 		testutil.Nil(t, q.tail)
 		testutil.Equal(t, 0, q.len())
 
-		testutil.Equal(t, false, q.has(n0.Node().id))
-		testutil.Equal(t, false, q.has(n1.Node().id))
-		testutil.Equal(t, false, q.has(n2.Node().id))
-		testutil.Equal(t, false, q.has(n3.Node().id))
+		testutil.Equal(t, false, q.has(n0.Node()))
+		testutil.Equal(t, false, q.has(n1.Node()))
+		testutil.Equal(t, false, q.has(n2.Node()))
+		testutil.Equal(t, false, q.has(n3.Node()))
 
 		testutil.Nil(t, n.Node().nextInRecomputeHeap)
 		testutil.Nil(t, n.Node().previousInRecomputeHeap)
@@ -260,12 +259,12 @@ func Test_recomputeHeapList_push_pop(t *testing.T) {
 		id, n, ok = q.pop()
 		testutil.Equal(t, false, ok)
 		testutil.Nil(t, n)
-		testutil.Equal(t, zeroID, id)
+		testutil.Nil(t, id)
 
-		testutil.Equal(t, false, q.has(n0.Node().id))
-		testutil.Equal(t, false, q.has(n1.Node().id))
-		testutil.Equal(t, false, q.has(n2.Node().id))
-		testutil.Equal(t, false, q.has(n3.Node().id))
+		testutil.Equal(t, false, q.has(n0.Node()))
+		testutil.Equal(t, false, q.has(n1.Node()))
+		testutil.Equal(t, false, q.has(n2.Node()))
+		testutil.Equal(t, false, q.has(n3.Node()))
 	}
 
 	q.push(n0)
@@ -291,31 +290,31 @@ func Test_recomputeHeapList_remove_0(t *testing.T) {
 	q.push(n4)
 
 	testutil.NotNil(t, rhnext(q.head))
-	testutil.Equal(t, nodePtrID(q.head), n0.n.id)
+	testutil.Equal(t, nodePtrID(q.head), n0.n.ID())
 	testutil.NotNil(t, rhprev(q.tail))
-	testutil.Equal(t, nodePtrID(q.tail), n4.n.id)
+	testutil.Equal(t, nodePtrID(q.tail), n4.n.ID())
 
-	ok := q.remove(n0.Node().id)
+	ok := q.remove(n0.Node())
 
 	testutil.Equal(t, true, ok)
 	testutil.Equal(t, 4, q.len())
 	testutil.NotNil(t, q.head)
-	testutil.Equal(t, nodePtrID(q.head), n1.n.id)
+	testutil.Equal(t, nodePtrID(q.head), n1.n.ID())
 	testutil.NotNil(t, q.tail)
-	testutil.Equal(t, nodePtrID(q.tail), n4.n.id)
+	testutil.Equal(t, nodePtrID(q.tail), n4.n.ID())
 
 	testutil.Nil(t, n0.Node().nextInRecomputeHeap)
 	testutil.Nil(t, n0.Node().previousInRecomputeHeap)
 
-	testutil.Equal(t, n1.Node().id, nodePtrID(q.head))
-	testutil.Equal(t, n2.Node().id, nodePtrID(rhnext(q.head)))
-	testutil.Equal(t, n3.Node().id, nodePtrID(rhnext(rhnext(q.head))))
-	testutil.Equal(t, n4.Node().id, nodePtrID(rhnext(rhnext(rhnext(q.head)))))
+	testutil.Equal(t, n1.Node().ID(), nodePtrID(q.head))
+	testutil.Equal(t, n2.Node().ID(), nodePtrID(rhnext(q.head)))
+	testutil.Equal(t, n3.Node().ID(), nodePtrID(rhnext(rhnext(q.head))))
+	testutil.Equal(t, n4.Node().ID(), nodePtrID(rhnext(rhnext(rhnext(q.head)))))
 
-	testutil.Equal(t, n4.Node().id, nodePtrID(q.tail))
-	testutil.Equal(t, n3.Node().id, nodePtrID(rhprev(q.tail)))
-	testutil.Equal(t, n2.Node().id, nodePtrID(rhprev(rhprev(q.tail))))
-	testutil.Equal(t, n1.Node().id, nodePtrID(rhprev(rhprev(rhprev(q.tail)))))
+	testutil.Equal(t, n4.Node().ID(), nodePtrID(q.tail))
+	testutil.Equal(t, n3.Node().ID(), nodePtrID(rhprev(q.tail)))
+	testutil.Equal(t, n2.Node().ID(), nodePtrID(rhprev(rhprev(q.tail))))
+	testutil.Equal(t, n1.Node().ID(), nodePtrID(rhprev(rhprev(rhprev(q.tail)))))
 }
 
 func Test_recomputeHeapList_remove_1(t *testing.T) {
@@ -335,31 +334,31 @@ func Test_recomputeHeapList_remove_1(t *testing.T) {
 	q.push(n4)
 
 	testutil.NotNil(t, rhnext(q.head))
-	testutil.Equal(t, nodePtrID(q.head), n0.n.id)
+	testutil.Equal(t, nodePtrID(q.head), n0.n.ID())
 	testutil.NotNil(t, rhprev(q.tail))
-	testutil.Equal(t, nodePtrID(q.tail), n4.n.id)
+	testutil.Equal(t, nodePtrID(q.tail), n4.n.ID())
 
-	ok := q.remove(n1.Node().id)
+	ok := q.remove(n1.Node())
 
 	testutil.Equal(t, true, ok)
 	testutil.Equal(t, 4, q.len())
 	testutil.NotNil(t, q.head)
-	testutil.Equal(t, nodePtrID(q.head), n0.n.id)
+	testutil.Equal(t, nodePtrID(q.head), n0.n.ID())
 	testutil.NotNil(t, q.tail)
-	testutil.Equal(t, nodePtrID(q.tail), n4.n.id)
+	testutil.Equal(t, nodePtrID(q.tail), n4.n.ID())
 
 	testutil.Nil(t, n1.Node().nextInRecomputeHeap)
 	testutil.Nil(t, n1.Node().previousInRecomputeHeap)
 
-	testutil.Equal(t, n0.Node().id, nodePtrID(q.head))
-	testutil.Equal(t, n2.Node().id, nodePtrID(rhnext(q.head)))
-	testutil.Equal(t, n3.Node().id, nodePtrID(rhnext(rhnext(q.head))))
-	testutil.Equal(t, n4.Node().id, nodePtrID(rhnext(rhnext(rhnext(q.head)))))
+	testutil.Equal(t, n0.Node().ID(), nodePtrID(q.head))
+	testutil.Equal(t, n2.Node().ID(), nodePtrID(rhnext(q.head)))
+	testutil.Equal(t, n3.Node().ID(), nodePtrID(rhnext(rhnext(q.head))))
+	testutil.Equal(t, n4.Node().ID(), nodePtrID(rhnext(rhnext(rhnext(q.head)))))
 
-	testutil.Equal(t, n4.Node().id, nodePtrID(q.tail))
-	testutil.Equal(t, n3.Node().id, nodePtrID(rhprev(q.tail)))
-	testutil.Equal(t, n2.Node().id, nodePtrID(rhprev(rhprev(q.tail))))
-	testutil.Equal(t, n0.Node().id, nodePtrID(rhprev(rhprev(rhprev(q.tail)))))
+	testutil.Equal(t, n4.Node().ID(), nodePtrID(q.tail))
+	testutil.Equal(t, n3.Node().ID(), nodePtrID(rhprev(q.tail)))
+	testutil.Equal(t, n2.Node().ID(), nodePtrID(rhprev(rhprev(q.tail))))
+	testutil.Equal(t, n0.Node().ID(), nodePtrID(rhprev(rhprev(rhprev(q.tail)))))
 }
 
 func Test_recomputeHeapList_remove_2(t *testing.T) {
@@ -379,31 +378,31 @@ func Test_recomputeHeapList_remove_2(t *testing.T) {
 	q.push(n4)
 
 	testutil.NotNil(t, rhnext(q.head))
-	testutil.Equal(t, nodePtrID(q.head), n0.n.id)
+	testutil.Equal(t, nodePtrID(q.head), n0.n.ID())
 	testutil.NotNil(t, rhprev(q.tail))
-	testutil.Equal(t, nodePtrID(q.tail), n4.n.id)
+	testutil.Equal(t, nodePtrID(q.tail), n4.n.ID())
 
-	ok := q.remove(n2.Node().id)
+	ok := q.remove(n2.Node())
 
 	testutil.Equal(t, true, ok)
 	testutil.Equal(t, 4, q.len())
 	testutil.NotNil(t, q.head)
-	testutil.Equal(t, nodePtrID(q.head), n0.n.id)
+	testutil.Equal(t, nodePtrID(q.head), n0.n.ID())
 	testutil.NotNil(t, q.tail)
-	testutil.Equal(t, nodePtrID(q.tail), n4.n.id)
+	testutil.Equal(t, nodePtrID(q.tail), n4.n.ID())
 
 	testutil.Nil(t, n2.Node().nextInRecomputeHeap)
 	testutil.Nil(t, n2.Node().previousInRecomputeHeap)
 
-	testutil.Equal(t, n0.Node().id, nodePtrID(q.head))
-	testutil.Equal(t, n1.Node().id, nodePtrID(rhnext(q.head)))
-	testutil.Equal(t, n3.Node().id, nodePtrID(rhnext(rhnext(q.head))))
-	testutil.Equal(t, n4.Node().id, nodePtrID(rhnext(rhnext(rhnext(q.head)))))
+	testutil.Equal(t, n0.Node().ID(), nodePtrID(q.head))
+	testutil.Equal(t, n1.Node().ID(), nodePtrID(rhnext(q.head)))
+	testutil.Equal(t, n3.Node().ID(), nodePtrID(rhnext(rhnext(q.head))))
+	testutil.Equal(t, n4.Node().ID(), nodePtrID(rhnext(rhnext(rhnext(q.head)))))
 
-	testutil.Equal(t, n4.Node().id, nodePtrID(q.tail))
-	testutil.Equal(t, n3.Node().id, nodePtrID(rhprev(q.tail)))
-	testutil.Equal(t, n1.Node().id, nodePtrID(rhprev(rhprev(q.tail))))
-	testutil.Equal(t, n0.Node().id, nodePtrID(rhprev(rhprev(rhprev(q.tail)))))
+	testutil.Equal(t, n4.Node().ID(), nodePtrID(q.tail))
+	testutil.Equal(t, n3.Node().ID(), nodePtrID(rhprev(q.tail)))
+	testutil.Equal(t, n1.Node().ID(), nodePtrID(rhprev(rhprev(q.tail))))
+	testutil.Equal(t, n0.Node().ID(), nodePtrID(rhprev(rhprev(rhprev(q.tail)))))
 }
 
 func Test_recomputeHeapList_remove_3(t *testing.T) {
@@ -423,31 +422,31 @@ func Test_recomputeHeapList_remove_3(t *testing.T) {
 	q.push(n4)
 
 	testutil.NotNil(t, rhnext(q.head))
-	testutil.Equal(t, nodePtrID(q.head), n0.n.id)
+	testutil.Equal(t, nodePtrID(q.head), n0.n.ID())
 	testutil.NotNil(t, rhprev(q.tail))
-	testutil.Equal(t, nodePtrID(q.tail), n4.n.id)
+	testutil.Equal(t, nodePtrID(q.tail), n4.n.ID())
 
-	ok := q.remove(n3.Node().id)
+	ok := q.remove(n3.Node())
 
 	testutil.Equal(t, true, ok)
 	testutil.Equal(t, 4, q.len())
 	testutil.NotNil(t, q.head)
-	testutil.Equal(t, nodePtrID(q.head), n0.n.id)
+	testutil.Equal(t, nodePtrID(q.head), n0.n.ID())
 	testutil.NotNil(t, q.tail)
-	testutil.Equal(t, nodePtrID(q.tail), n4.n.id)
+	testutil.Equal(t, nodePtrID(q.tail), n4.n.ID())
 
 	testutil.Nil(t, n3.Node().nextInRecomputeHeap)
 	testutil.Nil(t, n3.Node().previousInRecomputeHeap)
 
-	testutil.Equal(t, n0.Node().id, nodePtrID(q.head))
-	testutil.Equal(t, n1.Node().id, nodePtrID(rhnext(q.head)))
-	testutil.Equal(t, n2.Node().id, nodePtrID(rhnext(rhnext(q.head))))
-	testutil.Equal(t, n4.Node().id, nodePtrID(rhnext(rhnext(rhnext(q.head)))))
+	testutil.Equal(t, n0.Node().ID(), nodePtrID(q.head))
+	testutil.Equal(t, n1.Node().ID(), nodePtrID(rhnext(q.head)))
+	testutil.Equal(t, n2.Node().ID(), nodePtrID(rhnext(rhnext(q.head))))
+	testutil.Equal(t, n4.Node().ID(), nodePtrID(rhnext(rhnext(rhnext(q.head)))))
 
-	testutil.Equal(t, n4.Node().id, nodePtrID(q.tail))
-	testutil.Equal(t, n2.Node().id, nodePtrID(rhprev(q.tail)))
-	testutil.Equal(t, n1.Node().id, nodePtrID(rhprev(rhprev(q.tail))))
-	testutil.Equal(t, n0.Node().id, nodePtrID(rhprev(rhprev(rhprev(q.tail)))))
+	testutil.Equal(t, n4.Node().ID(), nodePtrID(q.tail))
+	testutil.Equal(t, n2.Node().ID(), nodePtrID(rhprev(q.tail)))
+	testutil.Equal(t, n1.Node().ID(), nodePtrID(rhprev(rhprev(q.tail))))
+	testutil.Equal(t, n0.Node().ID(), nodePtrID(rhprev(rhprev(rhprev(q.tail)))))
 }
 
 func Test_recomputeHeapList_remove_4(t *testing.T) {
@@ -467,37 +466,37 @@ func Test_recomputeHeapList_remove_4(t *testing.T) {
 	q.push(n4)
 
 	testutil.NotNil(t, rhnext(q.head))
-	testutil.Equal(t, nodePtrID(q.head), n0.n.id)
+	testutil.Equal(t, nodePtrID(q.head), n0.n.ID())
 	testutil.NotNil(t, rhprev(q.tail))
-	testutil.Equal(t, nodePtrID(q.tail), n4.n.id)
+	testutil.Equal(t, nodePtrID(q.tail), n4.n.ID())
 
-	ok := q.remove(n4.Node().id)
+	ok := q.remove(n4.Node())
 
 	testutil.Equal(t, true, ok)
 	testutil.Equal(t, 4, q.len())
 	testutil.NotNil(t, q.head)
-	testutil.Equal(t, nodePtrID(q.head), n0.n.id)
+	testutil.Equal(t, nodePtrID(q.head), n0.n.ID())
 	testutil.NotNil(t, q.tail)
-	testutil.Equal(t, nodePtrID(q.tail), n3.n.id)
+	testutil.Equal(t, nodePtrID(q.tail), n3.n.ID())
 
 	testutil.Nil(t, n4.Node().nextInRecomputeHeap)
 	testutil.Nil(t, n4.Node().previousInRecomputeHeap)
 
-	testutil.Equal(t, n0.Node().id, nodePtrID(q.head))
-	testutil.Equal(t, n1.Node().id, nodePtrID(rhnext(q.head)))
-	testutil.Equal(t, n2.Node().id, nodePtrID(rhnext(rhnext(q.head))))
-	testutil.Equal(t, n3.Node().id, nodePtrID(rhnext(rhnext(rhnext(q.head)))))
+	testutil.Equal(t, n0.Node().ID(), nodePtrID(q.head))
+	testutil.Equal(t, n1.Node().ID(), nodePtrID(rhnext(q.head)))
+	testutil.Equal(t, n2.Node().ID(), nodePtrID(rhnext(rhnext(q.head))))
+	testutil.Equal(t, n3.Node().ID(), nodePtrID(rhnext(rhnext(rhnext(q.head)))))
 
-	testutil.Equal(t, n3.Node().id, nodePtrID(q.tail))
-	testutil.Equal(t, n2.Node().id, nodePtrID(rhprev(q.tail)))
-	testutil.Equal(t, n1.Node().id, nodePtrID(rhprev(rhprev(q.tail))))
-	testutil.Equal(t, n0.Node().id, nodePtrID(rhprev(rhprev(rhprev(q.tail)))))
+	testutil.Equal(t, n3.Node().ID(), nodePtrID(q.tail))
+	testutil.Equal(t, n2.Node().ID(), nodePtrID(rhprev(q.tail)))
+	testutil.Equal(t, n1.Node().ID(), nodePtrID(rhprev(rhprev(q.tail))))
+	testutil.Equal(t, n0.Node().ID(), nodePtrID(rhprev(rhprev(rhprev(q.tail)))))
 }
 
 func Test_recomputeHeapList_remove_empty(t *testing.T) {
 	q := new(recomputeHeapList)
 
-	ok := q.remove(NewIdentifier())
+	ok := q.remove(NewNode("test"))
 	testutil.Equal(t, false, ok)
 }
 
@@ -516,11 +515,11 @@ func Test_recomputeHeapList_remove_notFound(t *testing.T) {
 	q.push(n3)
 
 	testutil.NotNil(t, rhnext(q.head))
-	testutil.Equal(t, nodePtrID(q.head), n0.n.id)
+	testutil.Equal(t, nodePtrID(q.head), n0.n.ID())
 	testutil.NotNil(t, rhprev(q.tail))
-	testutil.Equal(t, nodePtrID(q.tail), n3.n.id)
+	testutil.Equal(t, nodePtrID(q.tail), n3.n.ID())
 
-	ok := q.remove(NewIdentifier())
+	ok := q.remove(NewNode("test"))
 	testutil.Equal(t, false, ok)
 }
 
@@ -539,14 +538,14 @@ func Test_recomputeHeapList_remove_head(t *testing.T) {
 	q.push(n3)
 
 	testutil.NotNil(t, rhnext(q.head))
-	testutil.Equal(t, nodePtrID(q.head), n0.n.id)
+	testutil.Equal(t, nodePtrID(q.head), n0.n.ID())
 	testutil.NotNil(t, rhprev(q.tail))
-	testutil.Equal(t, nodePtrID(q.tail), n3.n.id)
+	testutil.Equal(t, nodePtrID(q.tail), n3.n.ID())
 
-	ok := q.remove(n0.Node().id)
+	ok := q.remove(n0.Node())
 	testutil.Equal(t, ok, true)
 	testutil.NotNil(t, q.head)
-	testutil.Equal(t, nodePtrID(q.head), n1.n.id)
+	testutil.Equal(t, nodePtrID(q.head), n1.n.ID())
 
 	testutil.Nil(t, n0.Node().nextInRecomputeHeap)
 	testutil.Nil(t, n0.Node().previousInRecomputeHeap)
@@ -567,14 +566,14 @@ func Test_recomputeHeapList_remove_tail(t *testing.T) {
 	q.push(n3)
 
 	testutil.NotNil(t, rhnext(q.head))
-	testutil.Equal(t, nodePtrID(q.head), n0.n.id)
+	testutil.Equal(t, nodePtrID(q.head), n0.n.ID())
 	testutil.NotNil(t, rhprev(q.tail))
-	testutil.Equal(t, nodePtrID(q.tail), n3.n.id)
+	testutil.Equal(t, nodePtrID(q.tail), n3.n.ID())
 
-	ok := q.remove(n3.Node().id)
+	ok := q.remove(n3.Node())
 	testutil.Equal(t, ok, true)
 	testutil.NotNil(t, q.tail)
-	testutil.Equal(t, nodePtrID(q.tail), n2.n.id)
+	testutil.Equal(t, nodePtrID(q.tail), n2.n.ID())
 
 	testutil.Nil(t, n3.Node().nextInRecomputeHeap)
 	testutil.Nil(t, n3.Node().previousInRecomputeHeap)
@@ -599,8 +598,8 @@ func Test_recomputeHeapList_consume(t *testing.T) {
 	var seenIDs []Identifier
 	var seen []INode
 
-	q.consume(func(k Identifier, v INode) {
-		seenIDs = append(seenIDs, k)
+	q.consume(func(k *Node, v INode) {
+		seenIDs = append(seenIDs, k.ID())
 		seen = append(seen, v)
 	})
 
@@ -610,6 +609,7 @@ func Test_recomputeHeapList_consume(t *testing.T) {
 
 	testutil.Equal(t, 5, len(seenIDs))
 	testutil.Equal(t, 5, len(seen))
+	testutil.Equal(t, []INode{n0, n1, n2, n3, n4}, seen, "items should be consumed in the order they were pushed")
 
 	for _, n := range seen {
 		testutil.Nil(t, n.Node().nextInRecomputeHeap)
@@ -635,35 +635,35 @@ func Test_recomputeHeapList_removeHeadItem_full(t *testing.T) {
 
 	testutil.NotNil(t, q.head)
 	testutil.NotNil(t, rhnext(q.head))
-	testutil.Equal(t, n0.Node().id, nodePtrID(q.head))
-	testutil.Equal(t, n1.Node().id, nodePtrID(rhnext(q.head)))
-	testutil.Equal(t, n2.Node().id, nodePtrID(rhnext(rhnext(q.head))))
-	testutil.Equal(t, n3.Node().id, nodePtrID(rhnext(rhnext(rhnext(q.head)))))
-	testutil.Equal(t, n4.Node().id, nodePtrID(rhnext(rhnext(rhnext(rhnext(q.head))))))
+	testutil.Equal(t, n0.Node().ID(), nodePtrID(q.head))
+	testutil.Equal(t, n1.Node().ID(), nodePtrID(rhnext(q.head)))
+	testutil.Equal(t, n2.Node().ID(), nodePtrID(rhnext(rhnext(q.head))))
+	testutil.Equal(t, n3.Node().ID(), nodePtrID(rhnext(rhnext(rhnext(q.head)))))
+	testutil.Equal(t, n4.Node().ID(), nodePtrID(rhnext(rhnext(rhnext(rhnext(q.head))))))
 
 	testutil.NotNil(t, q.tail)
 	testutil.NotNil(t, rhprev(q.tail))
-	testutil.Equal(t, n4.Node().id, nodePtrID(q.tail))
-	testutil.Equal(t, n3.Node().id, nodePtrID(rhprev(q.tail)))
-	testutil.Equal(t, n2.Node().id, nodePtrID(rhprev(rhprev(q.tail))))
-	testutil.Equal(t, n1.Node().id, nodePtrID(rhprev(rhprev(rhprev(q.tail)))))
-	testutil.Equal(t, n0.Node().id, nodePtrID(rhprev(rhprev(rhprev(rhprev(q.tail))))))
+	testutil.Equal(t, n4.Node().ID(), nodePtrID(q.tail))
+	testutil.Equal(t, n3.Node().ID(), nodePtrID(rhprev(q.tail)))
+	testutil.Equal(t, n2.Node().ID(), nodePtrID(rhprev(rhprev(q.tail))))
+	testutil.Equal(t, n1.Node().ID(), nodePtrID(rhprev(rhprev(rhprev(q.tail)))))
+	testutil.Equal(t, n0.Node().ID(), nodePtrID(rhprev(rhprev(rhprev(rhprev(q.tail))))))
 
 	q.removeHeadItem()
 
 	testutil.NotNil(t, q.head)
 	testutil.NotNil(t, rhnext(q.head))
-	testutil.Equal(t, n1.Node().id, nodePtrID(q.head))
-	testutil.Equal(t, n2.Node().id, nodePtrID(rhnext(q.head)))
-	testutil.Equal(t, n3.Node().id, nodePtrID(rhnext(rhnext(q.head))))
-	testutil.Equal(t, n4.Node().id, nodePtrID(rhnext(rhnext(rhnext(q.head)))))
+	testutil.Equal(t, n1.Node().ID(), nodePtrID(q.head))
+	testutil.Equal(t, n2.Node().ID(), nodePtrID(rhnext(q.head)))
+	testutil.Equal(t, n3.Node().ID(), nodePtrID(rhnext(rhnext(q.head))))
+	testutil.Equal(t, n4.Node().ID(), nodePtrID(rhnext(rhnext(rhnext(q.head)))))
 
 	testutil.NotNil(t, q.tail)
 	testutil.NotNil(t, rhprev(q.tail))
-	testutil.Equal(t, n4.Node().id, nodePtrID(q.tail))
-	testutil.Equal(t, n3.Node().id, nodePtrID(rhprev(q.tail)))
-	testutil.Equal(t, n2.Node().id, nodePtrID(rhprev(rhprev(q.tail))))
-	testutil.Equal(t, n1.Node().id, nodePtrID(rhprev(rhprev(rhprev(q.tail)))))
+	testutil.Equal(t, n4.Node().ID(), nodePtrID(q.tail))
+	testutil.Equal(t, n3.Node().ID(), nodePtrID(rhprev(q.tail)))
+	testutil.Equal(t, n2.Node().ID(), nodePtrID(rhprev(rhprev(q.tail))))
+	testutil.Equal(t, n1.Node().ID(), nodePtrID(rhprev(rhprev(rhprev(q.tail)))))
 
 	testutil.Nil(t, n0.Node().nextInRecomputeHeap)
 	testutil.Nil(t, n0.Node().previousInRecomputeHeap)
@@ -679,10 +679,10 @@ func Test_recomputeHeapList_removeHeadItem_one(t *testing.T) {
 
 	testutil.NotNil(t, q.head)
 	testutil.Nil(t, rhnext(q.head))
-	testutil.Equal(t, n0.Node().id, nodePtrID(q.head))
+	testutil.Equal(t, n0.Node().ID(), nodePtrID(q.head))
 	testutil.NotNil(t, q.tail)
 	testutil.Nil(t, rhprev(q.tail))
-	testutil.Equal(t, n0.Node().id, nodePtrID(q.tail))
+	testutil.Equal(t, n0.Node().ID(), nodePtrID(q.tail))
 
 	q.removeHeadItem()
 
@@ -1,6 +1,7 @@
 package incr
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/wcharczuk/go-incr/testutil"
@@ -220,7 +221,7 @@ func Test_recomputeHeap_remove(t *testing.T) {
 		if h == nil {
 			continue
 		}
-		testutil.Equal(t, false, h.has(n21.n.id))
+		testutil.Equal(t, false, h.has(n21.n))
 	}
 
 	testutil.Equal(t, true, rh.has(n22))
@@ -241,13 +242,13 @@ func Test_recomputeHeap_remove(t *testing.T) {
 		if h == nil {
 			continue
 		}
-		testutil.Equal(t, false, h.has(n10.n.id))
+		testutil.Equal(t, false, h.has(n10.n))
 	}
 	for _, h := range rh.heights {
 		if h == nil {
 			continue
 		}
-		testutil.Equal(t, false, h.has(n11.n.id))
+		testutil.Equal(t, false, h.has(n11.n))
 	}
 }
 
@@ -473,64 +474,176 @@ func Test_recomputeHeap_removeMinUnsafe(t *testing.T) {
 
 	node, ok := rh.removeMinUnsafe()
 	testutil.Equal(t, true, ok)
-	testutil.Equal(t, n00.Node().id, node.Node().id)
+	testutil.Equal(t, n00.Node().ID(), node.Node().ID())
 
 	node, ok = rh.removeMinUnsafe()
 	testutil.Equal(t, true, ok)
-	testutil.Equal(t, n01.Node().id, node.Node().id)
+	testutil.Equal(t, n01.Node().ID(), node.Node().ID())
 
 	node, ok = rh.removeMinUnsafe()
 	testutil.Equal(t, true, ok)
-	testutil.Equal(t, n02.Node().id, node.Node().id)
+	testutil.Equal(t, n02.Node().ID(), node.Node().ID())
 
 	node, ok = rh.removeMinUnsafe()
 	testutil.Equal(t, true, ok)
-	testutil.Equal(t, n10.Node().id, node.Node().id)
+	testutil.Equal(t, n10.Node().ID(), node.Node().ID())
 
 	node, ok = rh.removeMinUnsafe()
 	testutil.Equal(t, true, ok)
-	testutil.Equal(t, n11.Node().id, node.Node().id)
+	testutil.Equal(t, n11.Node().ID(), node.Node().ID())
 
 	node, ok = rh.removeMinUnsafe()
 	testutil.Equal(t, true, ok)
-	testutil.Equal(t, n12.Node().id, node.Node().id)
+	testutil.Equal(t, n12.Node().ID(), node.Node().ID())
 
 	node, ok = rh.removeMinUnsafe()
 	testutil.Equal(t, true, ok)
-	testutil.Equal(t, n13.Node().id, node.Node().id)
+	testutil.Equal(t, n13.Node().ID(), node.Node().ID())
 
 	rh.add(n10)
 	rh.add(n11)
 
 	node, ok = rh.removeMinUnsafe()
 	testutil.Equal(t, true, ok)
-	testutil.Equal(t, n10.Node().id, node.Node().id)
+	testutil.Equal(t, n10.Node().ID(), node.Node().ID())
 
 	node, ok = rh.removeMinUnsafe()
 	testutil.Equal(t, true, ok)
-	testutil.Equal(t, n11.Node().id, node.Node().id)
+	testutil.Equal(t, n11.Node().ID(), node.Node().ID())
 
 	node, ok = rh.removeMinUnsafe()
 	testutil.Equal(t, true, ok)
-	testutil.Equal(t, n50.Node().id, node.Node().id)
+	testutil.Equal(t, n50.Node().ID(), node.Node().ID())
 
 	node, ok = rh.removeMinUnsafe()
 	testutil.Equal(t, true, ok)
-	testutil.Equal(t, n51.Node().id, node.Node().id)
+	testutil.Equal(t, n51.Node().ID(), node.Node().ID())
 
 	node, ok = rh.removeMinUnsafe()
 	testutil.Equal(t, true, ok)
-	testutil.Equal(t, n52.Node().id, node.Node().id)
+	testutil.Equal(t, n52.Node().ID(), node.Node().ID())
 
 	node, ok = rh.removeMinUnsafe()
 	testutil.Equal(t, true, ok)
-	testutil.Equal(t, n53.Node().id, node.Node().id)
+	testutil.Equal(t, n53.Node().ID(), node.Node().ID())
 
 	node, ok = rh.removeMinUnsafe()
 	testutil.Equal(t, true, ok)
-	testutil.Equal(t, n54.Node().id, node.Node().id)
+	testutil.Equal(t, n54.Node().ID(), node.Node().ID())
 
 	node, ok = rh.removeMinUnsafe()
 	testutil.Equal(t, false, ok)
 	testutil.Nil(t, node)
 }
+
+func Test_recomputeHeap_removeAll(t *testing.T) {
+	g := New()
+	rh := newRecomputeHeap(32)
+
+	n10 := newHeightIncr(g, 1)
+	n50 := newHeightIncr(g, 5)
+	n51 := newHeightIncr(g, 5)
+	n30 := newHeightIncr(g, 3)
+	rh.add(n50, n10, n51, n30)
+
+	nodes := rh.removeAll()
+	testutil.Nil(t, rh.sanityCheck())
+	testutil.Equal(t, 0, rh.len())
+	testutil.Equal(t, 4, len(nodes))
+	testutil.Equal(t, n10.Node().ID(), nodes[0].Node().ID())
+	testutil.Equal(t, n30.Node().ID(), nodes[1].Node().ID())
+	for _, n := range nodes {
+		testutil.Equal(t, HeightUnset, n.Node().heightInRecomputeHeap)
+		testutil.Nil(t, n.Node().nextInRecomputeHeap)
+	}
+
+	rh.add(n30)
+	testutil.Equal(t, 1, rh.len())
+	testutil.Equal(t, 3, rh.minHeight)
+}
+
+func Test_recomputeHeap_addIfStaleConcurrent(t *testing.T) {
+	g := New()
+	rh := newRecomputeHeap(8)
+
+	newStaleHeightIncr := func(height int) *heightIncr {
+		n := newHeightIncr(g, height)
+		n.n.valid = true
+		n.n.forceNecessary = true
+		n.n.heightInRecomputeHeap = HeightUnset
+		return n
+	}
+
+	// the existing node sets the bounds the concurrent adds are folded into.
+	existing := newStaleHeightIncr(4)
+	rh.add(existing)
+
+	var nodes []INode
+	for height := 2; height < 16; height++ {
+		for x := 0; x < 8; x++ {
+			nodes = append(nodes, newStaleHeightIncr(height))
+		}
+	}
+	notStale := newHeightIncr(g, 3)
+	notStale.n.heightInRecomputeHeap = HeightUnset
+
+	wg := new(sync.WaitGroup)
+	for worker := 0; worker < 4; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// each worker adds every node to make sure
+			// they're only added to the heap once.
+			for _, n := range nodes {
+				rh.addIfStaleConcurrent(n)
+			}
+			rh.addIfStaleConcurrent(existing)
+			rh.addIfStaleConcurrent(notStale)
+		}()
+	}
+	wg.Wait()
+
+	testutil.Equal(t, len(nodes)+1, rh.len())
+	testutil.Equal(t, HeightUnset, notStale.n.heightInRecomputeHeap)
+
+	rh.foldPending()
+	testutil.Nil(t, rh.sanityCheck())
+	testutil.Equal(t, len(nodes)+1, rh.numItems)
+	testutil.Equal(t, 2, rh.minHeight)
+	testutil.Equal(t, 15, rh.maxHeight)
+	testutil.Equal(t, 0, rh.pendingItems)
+
+	for x := 2; x < 16; x++ {
+		var iter recomputeHeapListIter
+		rh.removeMinHeightIter(&iter)
+		values := iterToArray(iter.Next)
+		if x == 4 {
+			testutil.Equal(t, 9, len(values))
+		} else {
+			testutil.Equal(t, 8, len(values))
+		}
+	}
+	testutil.Equal(t, 0, rh.len())
+}
+
+func Test_recomputeHeap_foldPending_empty(t *testing.T) {
+	g := New()
+	rh := newRecomputeHeap(8)
+
+	// leave stale bounds from nodes that were removed.
+	n0 := newHeightIncr(g, 1)
+	rh.add(n0)
+	rh.remove(n0)
+
+	n1 := newHeightIncr(g, 5)
+	n1.n.valid = true
+	n1.n.forceNecessary = true
+	n1.n.heightInRecomputeHeap = HeightUnset
+	rh.addIfStaleConcurrent(n1)
+
+	rh.foldPending()
+	testutil.Nil(t, rh.sanityCheck())
+	testutil.Equal(t, 1, rh.numItems)
+	testutil.Equal(t, 5, rh.minHeight)
+	testutil.Equal(t, 5, rh.maxHeight)
+}
@@ -47,9 +47,21 @@ func GraphForNode(node INode) *Graph {
 //
 // If you're within a bind, you should pass the scope that is passed to your bind function.
 type Scope interface {
+	// Defer registers a function to be called when the scope is disposed,
+	// for example to release a subscription or file handle that was
+	// allocated while building the scope.
+	//
+	// Deferred functions are called in the reverse order they were registered.
+	// The top scope (the [Graph] itself) is never disposed, and as a result
+	// functions deferred on the graph are never called.
+	Defer(func())
+
 	isTopScope() bool
 	isScopeValid() bool
 	isScopeNecessary() bool
+	// isScopeBuilding returns if the scope is being built by
+	// a bind function on behalf of the stabilization.
+	isScopeBuilding() bool
 	scopeGraph() *Graph
 	scopeHeight() int
 	addScopeNode(INode)
@@ -1,6 +1,9 @@
 package incr
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Sentinel returns a node that evaluates a staleness function for each stabilization.
 //
@@ -13,25 +16,60 @@ import "context"
 // nodes on the result of a function withouth having to mark those nodes explicitly stale. Sentinels
 // are somewhat expensive as a result, and should only be used sparingly, and even
 // then only in situations where the stalness function will return true infequently.
-func Sentinel(scope Scope, fn func() bool, watched INode) SentinelIncr {
+//
+// To limit how often the staleness function is evaluated, pass a minimum check interval
+// with [OptSentinelInterval].
+func Sentinel(scope Scope, fn func() bool, watched INode, opts ...SentinelOption) SentinelIncr {
 	return SentinelContext(scope, func(_ context.Context) (bool, error) {
 		return fn(), nil
-	}, watched)
+	}, watched, opts...)
 }
 
 // SentinelContext returns a node that evaluates a staleness function for each stabilization, similar
 // to [Sentinel], except that the predicate is passed the stabilization context and can return an error.
-func SentinelContext(scope Scope, fn func(context.Context) (bool, error), watched INode) SentinelIncr {
+func SentinelContext(scope Scope, fn func(context.Context) (bool, error), watched INode, opts ...SentinelOption) SentinelIncr {
+	var options SentinelOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
 	s := WithinScope(scope, &sentinelIncr{
-		n:       NewNode("sentinel"),
-		fn:      fn,
-		watched: watched,
+		n:        NewNode("sentinel"),
+		fn:       fn,
+		watched:  watched,
+		interval: options.Interval,
 	})
 	graph := scope.scopeGraph()
+	if graph.lockStructureUnlessBuilding(scope, watched.Node().createdIn) {
+		defer graph.unlockStructure()
+	}
 	_ = graph.watchNode(s, watched)
 	return s
 }
 
+// SentinelOptions are options for sentinels.
+type SentinelOptions struct {
+	// Interval is the minimum time between evaluations of the staleness function,
+	// as measured by the graph clock (see [OptGraphClock]).
+	//
+	// A value of zero (the default) means the function is evaluated for each stabilization.
+	Interval time.Duration
+}
+
+// SentinelOption mutates SentinelOptions.
+type SentinelOption func(*SentinelOptions)
+
+// OptSentinelInterval sets the minimum time between evaluations of a sentinel's
+// staleness function; stabilizations in between skip the evaluation and treat the
+// watched node as not stale.
+//
+// The interval passing does not itself trigger a stabilization; the function is
+// evaluated by the first stabilization that starts after the interval has passed.
+func OptSentinelInterval(interval time.Duration) SentinelOption {
+	return func(o *SentinelOptions) {
+		o.Interval = interval
+	}
+}
+
 // SentinelIncr is a node that is recomputed always, but can cutoff
 // the computation based on the result of a provided deligate.
 type SentinelIncr interface {
@@ -41,10 +79,32 @@ type SentinelIncr interface {
 	ISentinel
 }
 
+var (
+	_ SentinelIncr   = (*sentinelIncr)(nil)
+	_ sentinelChecks = (*sentinelIncr)(nil)
+)
+
+// sentinelChecks is implemented by sentinels whose staleness function can be
+// evaluated ahead of the main recompute pass.
+type sentinelChecks interface {
+	ISentinel
+	isCheckDue(time.Time) bool
+	check(context.Context)
+}
+
 type sentinelIncr struct {
-	n       *Node
-	fn      func(context.Context) (bool, error)
-	watched INode
+	n        *Node
+	fn       func(context.Context) (bool, error)
+	watched  INode
+	interval time.Duration
+
+	// lastChecked is the graph time the staleness function was last evaluated.
+	lastChecked time.Time
+	// checkedAt is the stabilization number the staleness function was last
+	// evaluated, and checkStale and checkErr are the results of that evaluation.
+	checkedAt  uint64
+	checkStale bool
+	checkErr   error
 }
 
 func (s *sentinelIncr) Node() *Node { return s.n }
@@ -54,12 +114,33 @@ func (s *sentinelIncr) Always() {}
 func (s *sentinelIncr) Stale() bool { return true }
 
 func (s *sentinelIncr) Cutoff(ctx context.Context) (bool, error) {
-	isStale, err := s.fn(ctx)
-	return !isStale, err
+	graph := GraphForNode(s)
+	if s.checkedAt != graph.stabilizationNum {
+		if !s.isCheckDue(graph.stabilizationStarted) {
+			return true, nil
+		}
+		s.check(ctx)
+	}
+	return !s.checkStale, s.checkErr
+}
+
+func (s *sentinelIncr) isCheckDue(now time.Time) bool {
+	return s.interval == 0 || s.lastChecked.IsZero() || now.Sub(s.lastChecked) >= s.interval
+}
+
+// check evaluates the staleness function and records the result
+// for the current stabilization.
+func (s *sentinelIncr) check(ctx context.Context) {
+	graph := GraphForNode(s)
+	s.lastChecked = graph.stabilizationStarted
+	s.checkedAt = graph.stabilizationNum
+	s.checkStale, s.checkErr = s.fn(ctx)
 }
 
 func (s *sentinelIncr) Unwatch(_ context.Context) {
 	graph := s.n.createdIn.scopeGraph()
+	graph.lockStructure()
+	defer graph.unlockStructure()
 	graph.unwatchNode(s, s.watched)
 	s.watched = nil
 }
@@ -67,3 +148,30 @@ func (s *sentinelIncr) Unwatch(_ context.Context) {
 func (s *sentinelIncr) String() string {
 	return s.n.String()
 }
+
+// checkSentinels evaluates the staleness functions of the sentinels in the
+// recompute heap that are due to be checked in parallel, if the graph is
+// configured to do so with [OptGraphParallelSentinels], such that the
+// sentinels use the results when they're recomputed.
+func (graph *Graph) checkSentinels(ctx context.Context) {
+	if !graph.parallelSentinels {
+		return
+	}
+	due := graph.dueSentinelsBuffer[:0]
+	graph.sentinelsMu.Lock()
+	for _, sn := range graph.sentinels {
+		if sn == nil || sn.Node().heightInRecomputeHeap == HeightUnset {
+			continue
+		}
+		if typed, ok := sn.(sentinelChecks); ok && typed.isCheckDue(graph.stabilizationStarted) {
+			due = append(due, typed)
+		}
+	}
+	graph.sentinelsMu.Unlock()
+	_ = parallelBatch(ctx, graph.workerPool, func(ctx context.Context, sn sentinelChecks) error {
+		sn.check(ctx)
+		return nil
+	}, due)
+	clear(due)
+	graph.dueSentinelsBuffer = due[:0]
+}
@@ -1,8 +1,11 @@
 package incr
 
 import (
+	"context"
 	"fmt"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/wcharczuk/go-incr/testutil"
 )
@@ -130,3 +133,147 @@ func Test_Sentinel_withinBind(t *testing.T) {
 
 	testutil.Equal(t, 4, updates)
 }
+
+func Test_Sentinel_withinBind_concurrent(t *testing.T) {
+	ctx := testContext()
+	g := New(OptGraphConcurrent(true))
+
+	var stale atomic.Bool
+	var s SentinelIncr
+	bv := Var(g, "a")
+	b := Bind(g, bv, func(bs Scope, which string) Incr[string] {
+		m := Map(bs, Return(bs, which), ident)
+		s = Sentinel(bs, stale.Load, m)
+		return m
+	})
+	ob := MustObserve(g, b)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- g.Stabilize(ctx)
+	}()
+	select {
+	case err := <-done:
+		testutil.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("stabilization did not complete; adding a sentinel within a bind should not wait on stabilization")
+	}
+	testutil.Equal(t, "a", ob.Value())
+	testutil.Equal(t, true, g.HasSentinel(s))
+}
+
+func Test_Sentinel_interval(t *testing.T) {
+	ctx := testContext()
+	now := time.Date(2024, 01, 02, 03, 04, 05, 06, time.UTC)
+	g := New(OptGraphClock(func() time.Time { return now }))
+	v := Var(g, "foo")
+	var updates int
+	m := Map(g, v, func(vv string) string {
+		updates++
+		return vv
+	})
+	var checks int
+	_ = Sentinel(g, func() bool {
+		checks++
+		return true
+	}, m, OptSentinelInterval(time.Minute))
+	_ = MustObserve(g, m)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 1, checks)
+	testutil.Equal(t, 1, updates)
+
+	now = now.Add(30 * time.Second)
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 1, checks)
+	testutil.Equal(t, 1, updates)
+
+	now = now.Add(30 * time.Second)
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 2, checks)
+	testutil.Equal(t, 2, updates)
+}
+
+func Test_Sentinel_parallel(t *testing.T) {
+	ctx := testContext()
+	g := New(OptGraphParallelSentinels(true))
+
+	var checks, updates int32
+	stale := make([]atomic.Bool, 8)
+	for x := 0; x < len(stale); x++ {
+		x := x
+		m := Map(g, Return(g, x), func(vv int) int {
+			atomic.AddInt32(&updates, 1)
+			return vv
+		})
+		_ = Sentinel(g, func() bool {
+			atomic.AddInt32(&checks, 1)
+			return stale[x].Load()
+		}, m)
+		_ = MustObserve(g, m)
+	}
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 8, atomic.LoadInt32(&checks))
+	testutil.Equal(t, 8, atomic.LoadInt32(&updates))
+
+	stale[3].Store(true)
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 16, atomic.LoadInt32(&checks))
+	testutil.Equal(t, 9, atomic.LoadInt32(&updates))
+}
+
+func Test_Sentinel_parallel_concurrent(t *testing.T) {
+	ctx := testContext()
+	const sentinels = 4
+	g := New(OptGraphParallelSentinels(true), OptGraphParallelism(sentinels))
+
+	// each check waits for every check to be in progress, which
+	// only completes if the checks are called concurrently.
+	var arrived int32
+	var timedOut atomic.Bool
+	allArrived := make(chan struct{})
+	for x := 0; x < sentinels; x++ {
+		m := Map(g, Return(g, x), ident)
+		_ = Sentinel(g, func() bool {
+			if atomic.AddInt32(&arrived, 1) == sentinels {
+				close(allArrived)
+			}
+			select {
+			case <-allArrived:
+			case <-time.After(5 * time.Second):
+				timedOut.Store(true)
+			}
+			return false
+		}, m)
+		_ = MustObserve(g, m)
+	}
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, sentinels, atomic.LoadInt32(&arrived))
+	testutil.Equal(t, false, timedOut.Load(), "the sentinel checks should be in progress at the same time")
+}
+
+func Test_Sentinel_parallel_error(t *testing.T) {
+	ctx := testContext()
+	g := New(OptGraphParallelSentinels(true))
+	m := Map(g, Return(g, "foo"), ident)
+	s := SentinelContext(g, func(_ context.Context) (bool, error) {
+		return false, fmt.Errorf("this is only a test")
+	}, m)
+	var gotErr error
+	s.Node().OnError(func(_ context.Context, err error) {
+		gotErr = err
+	})
+	_ = MustObserve(g, m)
+
+	err := g.Stabilize(ctx)
+	testutil.Error(t, err)
+	testutil.Error(t, gotErr)
+}
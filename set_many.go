@@ -0,0 +1,99 @@
+package incr
+
+import "slices"
+
+// VarAssignment is a value to set on a var with [SetMany].
+//
+// Create a var assignment with [Assign].
+type VarAssignment struct {
+	v      INode
+	assign func(*Graph) (stale, notify bool)
+	err    error
+}
+
+// assignableVar is a var that can be set with [SetMany].
+type assignableVar[T any] interface {
+	INode
+	assign(*Graph, func(T) T) (stale, notify bool)
+}
+
+// Assign returns a var assignment that sets a given var to
+// a given value when it's passed to [SetMany].
+//
+// The var must be created with [Var] or [VarWithEqual], otherwise
+// [SetMany] returns [ErrUnsupportedVar] for the assignment.
+func Assign[T any](v VarIncr[T], value T) VarAssignment {
+	av, ok := v.(assignableVar[T])
+	if !ok {
+		return VarAssignment{v: v, err: ErrUnsupportedVar}
+	}
+	return VarAssignment{
+		v: av,
+		assign: func(graph *Graph) (bool, bool) {
+			return av.assign(graph, func(_ T) T { return value })
+		},
+	}
+}
+
+// SetMany sets the values of a number of vars at once.
+//
+// Each var is set as if [VarIncr.Set] was called for it, but the vars are marked
+// stale together, acquiring the recompute heap lock once rather than once per var.
+//
+// If the graph is concurrent the sets take effect together, that is a stabilization
+// will either see all of the values or none of them.
+//
+// The vars can belong to different graphs, in which case the vars of each
+// graph are set together.
+//
+// If any of the assignments is for a var that is not supported (see [Assign]),
+// an error is returned and none of the vars are set.
+func SetMany(assignments ...VarAssignment) error {
+	for _, a := range assignments {
+		if a.err != nil {
+			return a.err
+		}
+	}
+	var graphs []*Graph
+	for _, a := range assignments {
+		graph := GraphForNode(a.v)
+		if !slices.Contains(graphs, graph) {
+			graphs = append(graphs, graph)
+		}
+	}
+	for _, graph := range graphs {
+		graph.setMany(assignments)
+	}
+	return nil
+}
+
+// setMany applies the assignments for vars that belong to the graph.
+func (graph *Graph) setMany(assignments []VarAssignment) {
+	if graph.concurrent {
+		graph.stateMu.Lock()
+	}
+	var stale, notify []INode
+	for _, a := range assignments {
+		if GraphForNode(a.v) != graph {
+			continue
+		}
+		isStale, shouldNotify := a.assign(graph)
+		if isStale {
+			a.v.Node().setAt = graph.stabilizationNum
+			stale = append(stale, a.v)
+		}
+		if shouldNotify {
+			notify = append(notify, a.v)
+		}
+	}
+	graph.recomputeHeap.addIfNotPresent(stale...)
+	handlers := graph.onSetStale
+	if graph.concurrent {
+		graph.stateMu.Unlock()
+	}
+	for _, n := range notify {
+		for _, handler := range handlers {
+			handler.fn(n)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package incr
+
+import (
+	"testing"
+
+	"github.com/wcharczuk/go-incr/testutil"
+)
+
+func Test_SetMany(t *testing.T) {
+	ctx := testContext()
+	g := New()
+	v0 := Var(g, "foo")
+	v1 := Var(g, 1)
+	v2 := Var(g, "unobserved")
+	o := MustObserve(g, Map2(g, v0, v1, func(a string, b int) string {
+		return a + ":" + string(rune('0'+b))
+	}))
+
+	_ = g.Stabilize(ctx)
+	testutil.Equal(t, "foo:1", o.Value())
+
+	var notified []INode
+	g.OnSetStale(func(n INode) {
+		notified = append(notified, n)
+	})
+
+	err := SetMany(
+		Assign(v0, "bar"),
+		Assign(v1, 2),
+		Assign(v2, "still unobserved"),
+	)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "bar", v0.Value())
+	testutil.Equal(t, 2, v1.Value())
+	testutil.Equal(t, "still unobserved", v2.Value())
+	testutil.Equal(t, 2, g.recomputeHeap.len())
+	testutil.Equal(t, 2, len(notified))
+
+	_ = g.Stabilize(ctx)
+	testutil.Equal(t, "bar:2", o.Value())
+}
+
+func Test_SetMany_duringStabilization(t *testing.T) {
+	ctx := testContext()
+	g := New()
+	v0 := Var(g, 1)
+	v1 := Var(g, 2)
+	var setMany bool
+	m := Map2(g, v0, v1, func(a, b int) int {
+		if !setMany {
+			setMany = true
+			testutil.NoError(t, SetMany(Assign(v0, 10), Assign(v1, 20)))
+		}
+		return a + b
+	})
+	o := MustObserve(g, m)
+
+	_ = g.Stabilize(ctx)
+	testutil.Equal(t, 3, o.Value())
+	testutil.Equal(t, 10, v0.Value())
+	testutil.Equal(t, 20, v1.Value())
+
+	_ = g.Stabilize(ctx)
+	testutil.Equal(t, 30, o.Value())
+}
+
+func Test_SetMany_multipleGraphs(t *testing.T) {
+	ctx := testContext()
+	g0 := New()
+	g1 := New(OptGraphConcurrent(true))
+	v0 := Var(g0, "foo")
+	v1 := Var(g1, "bar")
+	o0 := MustObserve(g0, v0)
+	o1 := MustObserve(g1, v1)
+
+	err := SetMany(Assign(v0, "not-foo"), Assign(v1, "not-bar"))
+	testutil.NoError(t, err)
+	testutil.Equal(t, 1, g0.recomputeHeap.len())
+	testutil.Equal(t, 1, g1.recomputeHeap.len())
+
+	_ = g0.Stabilize(ctx)
+	_ = g1.Stabilize(ctx)
+	testutil.Equal(t, "not-foo", o0.Value())
+	testutil.Equal(t, "not-bar", o1.Value())
+}
+
+type unsupportedVar[T any] struct {
+	VarIncr[T]
+}
+
+func Test_SetMany_unsupportedVar(t *testing.T) {
+	g := New()
+	v0 := Var(g, "foo")
+	v1 := unsupportedVar[string]{Var(g, "bar")}
+
+	err := SetMany(Assign(v0, "not-foo"), Assign[string](v1, "not-bar"))
+	testutil.Equal(t, ErrUnsupportedVar, err)
+	testutil.Equal(t, "foo", v0.Value())
+	testutil.Equal(t, "bar", v1.Value())
+	testutil.Equal(t, 0, g.recomputeHeap.len())
+}
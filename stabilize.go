@@ -2,6 +2,8 @@ package incr
 
 import (
 	"context"
+	"sync/atomic"
+	"time"
 )
 
 // Stabilize kicks off the stabilization for nodes that have been observed by the graph's scope.
@@ -30,8 +32,91 @@ func (graph *Graph) Stabilize(ctx context.Context) (err error) {
 	defer func() {
 		graph.stabilizeEnd(ctx, err)
 	}()
+	err = graph.stabilize(ctx)
+	return
+}
+
+// StabilizeWithReport stabilizes the graph as [Graph.Stabilize] does, and returns
+// a report of the work done by the stabilization.
+//
+// The report is returned even if the stabilization returns an error, in which
+// case it describes the work done before the error.
+func (graph *Graph) StabilizeWithReport(ctx context.Context) (report StabilizationReport, err error) {
+	if err = graph.ensureNotStabilizing(ctx); err != nil {
+		return
+	}
+	ctx = graph.stabilizeStart(ctx)
+	started := graph.reportCounters()
+	graph.report = &report
+	defer func() {
+		graph.report = nil
+		graph.finishReport(&report, started)
+		graph.stabilizeEnd(ctx, err)
+	}()
+	err = graph.stabilize(ctx)
+	return
+}
+
+// StabilizationReport describes the work done by a stabilization.
+//
+// Get a report for a stabilization with [Graph.StabilizeWithReport].
+type StabilizationReport struct {
+	// StabilizationNum is the stabilization number of the stabilization.
+	StabilizationNum uint64
+	// Started is the time the stabilization started.
+	Started time.Time
+	// Elapsed is the time taken to recompute the nodes, and
+	// does not include the time taken by the update handlers.
+	Elapsed time.Duration
+	// NodesRecomputed is the number of nodes that were recomputed.
+	NodesRecomputed uint64
+	// NodesChanged is the number of nodes that were recomputed and
+	// not cut off, including a node that returned an error.
+	NodesChanged uint64
+	// NodesCutoff is the number of nodes that were recomputed
+	// but cut off, and as a result did not change.
+	NodesCutoff uint64
+	// ObserversChanged are the observers whose observed node changed.
+	ObserversChanged []IObserver
+	// BindScopesCreated is the number of times the function of a
+	// bind was evaluated, creating a new right-hand side scope.
+	BindScopesCreated uint64
+	// BindScopesDestroyed is the number of bind right-hand side scopes
+	// that were discarded, invalidating the nodes created in them.
+	BindScopesDestroyed uint64
+	// HeightsAdjusted is the number of times a node's height was
+	// raised because it was linked to a parent with a greater height.
+	HeightsAdjusted uint64
+}
+
+// reportCounters returns the graph counters that a report is computed from.
+func (graph *Graph) reportCounters() (counters StabilizationReport) {
+	counters.NodesRecomputed = atomic.LoadUint64(&graph.numNodesRecomputed)
+	counters.NodesChanged = atomic.LoadUint64(&graph.numNodesChanged)
+	counters.NodesCutoff = atomic.LoadUint64(&graph.numNodesCutoff)
+	counters.BindScopesCreated = atomic.LoadUint64(&graph.numBindScopesCreated)
+	counters.BindScopesDestroyed = atomic.LoadUint64(&graph.numBindScopesDestroyed)
+	counters.HeightsAdjusted = graph.adjustHeightsHeap.heightsAdjusted()
+	return
+}
+
+// finishReport fills in a report with the counters that changed since the stabilization started.
+func (graph *Graph) finishReport(report *StabilizationReport, started StabilizationReport) {
+	ended := graph.reportCounters()
+	report.StabilizationNum = graph.stabilizationNum
+	report.Started = graph.stabilizationStarted
+	report.Elapsed = time.Since(graph.stabilizationStartedMonotonic)
+	report.NodesRecomputed = ended.NodesRecomputed - started.NodesRecomputed
+	report.NodesChanged = ended.NodesChanged - started.NodesChanged
+	report.NodesCutoff = ended.NodesCutoff - started.NodesCutoff
+	report.BindScopesCreated = ended.BindScopesCreated - started.BindScopesCreated
+	report.BindScopesDestroyed = ended.BindScopesDestroyed - started.BindScopesDestroyed
+	report.HeightsAdjusted = ended.HeightsAdjusted - started.HeightsAdjusted
+}
 
-	var immediateRecompute []INode
+func (graph *Graph) stabilize(ctx context.Context) (err error) {
+	graph.checkSentinels(ctx)
+	immediateRecompute := graph.immediateRecomputeBuffer[:0]
 	var next INode
 	for graph.recomputeHeap.numItems > 0 {
 		next, _ = graph.recomputeHeap.removeMinUnsafe()
@@ -47,6 +132,8 @@ func (graph *Graph) Stabilize(ctx context.Context) (err error) {
 		for _, n := range immediateRecompute {
 			graph.recomputeHeap.addIfNotPresent(n)
 		}
+		clear(immediateRecompute)
 	}
+	graph.immediateRecomputeBuffer = immediateRecompute[:0]
 	return
 }
@@ -1420,3 +1420,97 @@ func Test_Stabilize_alwaysInRecomputeHeapOnError(t *testing.T) {
 	testutil.Error(t, err)
 	testutil.Equal(t, "this is only a test", err.Error())
 }
+
+func Test_Stabilize_steadyState_doesNotAllocate(t *testing.T) {
+	graph, vars := makeBenchmarkSteadyStateGraph(256)
+	ctx := context.Background()
+	values := []*string{ref("foo"), ref("bar")}
+	var index int
+	allocs := testing.AllocsPerRun(100, func() {
+		vars[index%len(vars)].Set(values[index%2])
+		index++
+		if err := graph.Stabilize(ctx); err != nil {
+			t.Fatal(err)
+		}
+	})
+	testutil.Equal(t, 0, allocs)
+}
+
+func Test_StabilizeWithReport(t *testing.T) {
+	ctx := testContext()
+	g := New()
+	v := Var(g, "a")
+	c := Cutoff(g, v, func(_, next string) bool { return next == "cutoff" })
+	m := Map(g, c, ident)
+	deep := Map(g, Map(g, Map(g, v, ident), ident), ident)
+	b := Bind(g, v, func(bs Scope, vv string) Incr[string] {
+		if vv == "cutoff" {
+			return deep
+		}
+		return Map(bs, Return(bs, vv), ident)
+	})
+	om := MustObserve(g, m)
+	ob := MustObserve(g, b)
+
+	report, err := g.StabilizeWithReport(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 1, report.StabilizationNum)
+	testutil.Equal(t, false, report.Started.IsZero())
+	testutil.Equal(t, 6, report.NodesRecomputed)
+	testutil.Equal(t, 6, report.NodesChanged)
+	testutil.Equal(t, 0, report.NodesCutoff)
+	testutil.Equal(t, []IObserver{om, ob}, report.ObserversChanged)
+	testutil.Equal(t, 1, report.BindScopesCreated)
+	testutil.Equal(t, 0, report.BindScopesDestroyed)
+	testutil.Equal(t, 1, report.HeightsAdjusted)
+
+	v.Set("cutoff")
+	report, err = g.StabilizeWithReport(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 2, report.StabilizationNum)
+	testutil.Equal(t, 7, report.NodesRecomputed)
+	testutil.Equal(t, 6, report.NodesChanged)
+	testutil.Equal(t, 1, report.NodesCutoff)
+	testutil.Equal(t, []IObserver{ob}, report.ObserversChanged)
+	testutil.Equal(t, 1, report.BindScopesCreated)
+	testutil.Equal(t, 1, report.BindScopesDestroyed)
+	testutil.Equal(t, "cutoff", ob.Value())
+
+	report, err = g.StabilizeWithReport(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, 3, report.StabilizationNum)
+	testutil.Equal(t, 0, report.NodesRecomputed)
+	testutil.Empty(t, report.ObserversChanged)
+}
+
+func Test_StabilizeWithReport_clock(t *testing.T) {
+	ctx := testContext()
+	now := time.Date(2024, 01, 02, 03, 04, 05, 0, time.UTC)
+	g := New(OptGraphClock(func() time.Time { return now }))
+	m := Map(g, Var(g, "a"), func(vv string) string {
+		time.Sleep(time.Millisecond)
+		return vv
+	})
+	_ = MustObserve(g, m)
+
+	report, err := g.StabilizeWithReport(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, now, report.Started)
+	testutil.Equal(t, true, report.Elapsed >= time.Millisecond, "the elapsed time should not be measured with the graph clock")
+}
+
+func Test_StabilizeWithReport_error(t *testing.T) {
+	ctx := testContext()
+	g := New()
+	v := Var(g, "a")
+	m0 := Map(g, v, ident)
+	m1 := MapContext(g, m0, func(_ context.Context, _ string) (string, error) {
+		return "", fmt.Errorf("this is only a test")
+	})
+	_ = MustObserve(g, m1)
+
+	report, err := g.StabilizeWithReport(ctx)
+	testutil.Error(t, err)
+	testutil.Equal(t, 2, report.NodesRecomputed)
+	testutil.Equal(t, 2, report.NodesChanged)
+}
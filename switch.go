@@ -0,0 +1,37 @@
+package incr
+
+// Switch returns an incremental that yields the value of the incremental
+// of the case whose key matches the current value of the key incremental,
+// or the value of the default incremental if no case matches.
+//
+// Only the selected incremental is linked to the switch, so incrementals
+// of other cases are not recomputed on account of the switch.
+//
+// The default may be nil, in which case the switch yields the zero
+// value of the type if no case matches.
+func Switch[K comparable, A any](scope Scope, key Incr[K], def Incr[A], cases ...SwitchCase[K, A]) Incr[A] {
+	byKey := make(map[K]Incr[A], len(cases))
+	for _, c := range cases {
+		byKey[c.Key] = c.Value
+	}
+	selected := Map(scope, key, func(kv K) Incr[A] {
+		if value, ok := byKey[kv]; ok {
+			return value
+		}
+		return def
+	})
+	selected.Node().SetKind("switch")
+	return Join(scope, selected)
+}
+
+// Case returns a case for a [Switch] that selects a given incremental
+// when the switch's key incremental yields a given key.
+func Case[K comparable, A any](key K, value Incr[A]) SwitchCase[K, A] {
+	return SwitchCase[K, A]{Key: key, Value: value}
+}
+
+// SwitchCase is a case for a [Switch].
+type SwitchCase[K comparable, A any] struct {
+	Key   K
+	Value Incr[A]
+}
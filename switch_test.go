@@ -0,0 +1,67 @@
+package incr
+
+import (
+	"testing"
+
+	"github.com/wcharczuk/go-incr/testutil"
+)
+
+func Test_Switch(t *testing.T) {
+	ctx := testContext()
+	g := New()
+
+	key := Var(g, "a")
+	a := Var(g, "a-value")
+	b := Var(g, "b-value")
+	def := Var(g, "default-value")
+	s := Switch(g, key, Incr[string](def),
+		Case[string, string]("a", a),
+		Case[string, string]("b", b),
+	)
+	o := MustObserve(g, s)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "a-value", o.Value())
+	testutil.Equal(t, false, g.Has(b))
+	testutil.Equal(t, false, g.Has(def))
+
+	key.Set("b")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "b-value", o.Value())
+	testutil.Equal(t, false, g.Has(a))
+
+	key.Set("c")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "default-value", o.Value())
+
+	def.Set("not-default-value")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "not-default-value", o.Value())
+}
+
+func Test_Switch_nilDefault(t *testing.T) {
+	ctx := testContext()
+	g := New()
+
+	key := Var(g, 0)
+	one := Return(g, "one")
+	o := MustObserve(g, Switch(g, key, nil, Case[int, string](1, one)))
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "", o.Value())
+
+	key.Set(1)
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "one", o.Value())
+
+	key.Set(2)
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "", o.Value())
+}
@@ -44,7 +44,10 @@ func (ti *timerIncr[A]) Parents() []INode {
 
 func (ti *timerIncr[A]) Node() *Node { return ti.n }
 
-func (ti *timerIncr[A]) Value() A { return ti.value }
+func (ti *timerIncr[A]) Value() A {
+	ti.n.checkRead(ti)
+	return ti.value
+}
 
 func (ti *timerIncr[A]) Always() {}
 
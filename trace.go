@@ -101,6 +101,22 @@ func WithStabilizationNumber(ctx context.Context, stabilizationNumber uint64) co
 	return context.WithValue(ctx, stabilizationNumberKey{}, stabilizationNumber)
 }
 
+// stabilizationContext is the context passed to nodes during stabilization.
+//
+// It's allocated for each stabilization, unless the graph reuses it between
+// stabilizations (see [Graph.stabilizationContext]).
+type stabilizationContext struct {
+	context.Context
+	stabilizationNum uint64
+}
+
+func (sc *stabilizationContext) Value(key any) any {
+	if _, ok := key.(stabilizationNumberKey); ok {
+		return sc.stabilizationNum
+	}
+	return sc.Context.Value(key)
+}
+
 // GetStabilizationNumber gets the stabilization number from a context.
 func GetStabilizationNumber(ctx context.Context) (stabilizationNumber uint64, ok bool) {
 	if value := ctx.Value(stabilizationNumberKey{}); value != nil {
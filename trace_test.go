@@ -50,3 +50,111 @@ func Test_WithTracingOutput(t *testing.T) {
 	Equal(t, false, strings.Contains(output.String(), "this is a errorf test"))
 	Equal(t, true, strings.Contains(errOutput.String(), "this is a errorf test"))
 }
+
+func Test_GetStabilizationNumber_stabilize(t *testing.T) {
+	g := New()
+	v := Var(g, "foo")
+	var seen []uint64
+	m := MapContext(g, v, func(ctx context.Context, value string) (string, error) {
+		stabilizationNumber, ok := GetStabilizationNumber(ctx)
+		Equal(t, true, ok)
+		seen = append(seen, stabilizationNumber)
+		return value, nil
+	})
+	_ = MustObserve(g, m)
+
+	ctx := context.Background()
+	err := g.Stabilize(ctx)
+	NoError(t, err)
+	v.Set("bar")
+	err = g.Stabilize(ctx)
+	NoError(t, err)
+	v.Set("baz")
+	err = g.Stabilize(WithTracingOutputs(ctx, new(bytes.Buffer), new(bytes.Buffer)))
+	NoError(t, err)
+	Equal(t, []uint64{1, 2, 3}, seen)
+
+	_, ok := GetStabilizationNumber(ctx)
+	Equal(t, false, ok)
+}
+
+func Test_GetStabilizationNumber_retained(t *testing.T) {
+	g := New(OptGraphConcurrent(true))
+	v := Var(g, "foo")
+	var retained []context.Context
+	m := MapContext(g, v, func(ctx context.Context, value string) (string, error) {
+		retained = append(retained, ctx)
+		return value, nil
+	})
+	a := Async(g, m, func(ctx context.Context, value string) Future[string] {
+		return Go(ctx, func(_ context.Context) (string, error) {
+			return value, nil
+		})
+	})
+	_ = MustObserve(g, a)
+
+	ctx := context.Background()
+	err := g.Stabilize(ctx)
+	NoError(t, err)
+	v.Set("bar")
+	err = g.Stabilize(ctx)
+	NoError(t, err)
+
+	Equal(t, 2, len(retained))
+	first, ok := GetStabilizationNumber(retained[0])
+	Equal(t, true, ok)
+	Equal(t, 1, first, "a retained context should keep its stabilization number")
+	second, ok := GetStabilizationNumber(retained[1])
+	Equal(t, true, ok)
+	Equal(t, 2, second)
+}
+
+type testContextKey struct{}
+
+func Test_Stabilize_retainedContext(t *testing.T) {
+	g := New()
+	v := Var(g, "foo")
+	var kept context.Context
+	m := MapContext(g, v, func(ctx context.Context, value string) (string, error) {
+		if kept == nil {
+			kept = ctx
+		}
+		return value, nil
+	})
+	_ = MustObserve(g, m)
+
+	parent, cancel := context.WithCancel(context.WithValue(context.Background(), testContextKey{}, "value"))
+	err := g.Stabilize(parent)
+	NoError(t, err)
+
+	v.Set("bar")
+	err = g.Stabilize(context.Background())
+	NoError(t, err)
+
+	Equal(t, "value", kept.Value(testContextKey{}), "a retained context should keep the values of its parent")
+	stabilizationNum, ok := GetStabilizationNumber(kept)
+	Equal(t, true, ok)
+	Equal(t, 1, stabilizationNum, "a retained context should keep its stabilization number")
+	cancel()
+	NotNil(t, kept.Err(), "a retained context should be canceled with its parent")
+}
+
+func Test_Stabilize_reuseStabilizationContext(t *testing.T) {
+	g := New(OptGraphReuseStabilizationContext(true))
+	v := Var(g, "foo")
+	var seen []context.Context
+	m := MapContext(g, v, func(ctx context.Context, value string) (string, error) {
+		seen = append(seen, ctx)
+		return value, nil
+	})
+	_ = MustObserve(g, m)
+
+	err := g.Stabilize(context.Background())
+	NoError(t, err)
+	v.Set("bar")
+	err = g.Stabilize(context.Background())
+	NoError(t, err)
+
+	Equal(t, 2, len(seen))
+	Equal(t, true, seen[0] == seen[1], "the context should be reused between stabilizations")
+}
@@ -3,6 +3,7 @@ package incr
 import (
 	"context"
 	"fmt"
+	"sync"
 	"sync/atomic"
 )
 
@@ -21,6 +22,23 @@ func Var[T any](scope Scope, t T) VarIncr[T] {
 	})
 }
 
+// VarWithEqual returns a new var node that uses a given equality function to
+// cut off recomputation of its children.
+//
+// If the var is set to a value that is equal to the value the var had when it last
+// changed, or its initial value if it hasn't changed, the var does not change and
+// its children are not recomputed.
+//
+// For comparable values you can pass [Equals] as the equality function.
+func VarWithEqual[T any](scope Scope, t T, equal func(T, T) bool) VarIncr[T] {
+	return WithinScope(scope, &varIncr[T]{
+		n:            NewNode("var"),
+		value:        t,
+		equal:        equal,
+		changedValue: t,
+	})
+}
+
 // VarIncr is a graph node type that implements an incremental variable.
 type VarIncr[T any] interface {
 	Incr[T]
@@ -28,7 +46,21 @@ type VarIncr[T any] interface {
 	// Set sets the var value.
 	//
 	// Calling [Set] will invalidate any nodes that reference this variable.
+	//
+	// If the graph is stabilizing the value will be staged and applied
+	// when the stabilization completes.
 	Set(T)
+
+	// Update sets the var value to the result of a given function called
+	// with the latest value of the var.
+	//
+	// If a value has been staged by [Set] or [Update] during stabilization, the
+	// function is called with the staged value rather than the value returned by [Value],
+	// such that updates made during stabilization compose.
+	//
+	// The function must not set the var itself, and if the graph is concurrent the function
+	// is called while the graph is locked, and must not set vars or mark nodes stale.
+	Update(func(T) T)
 }
 
 var (
@@ -36,15 +68,34 @@ var (
 	_ IShouldBeInvalidated = (*varIncr[string])(nil)
 	_ IStale               = (*varIncr[string])(nil)
 	_ IStabilize           = (*varIncr[string])(nil)
+	_ ICutoff              = (*varIncr[string])(nil)
 	_ fmt.Stringer         = (*varIncr[string])(nil)
+	_ stagedVar            = (*varIncr[string])(nil)
 )
 
+// stagedVar is a var that can stage sets made
+// during stabilization to be applied after.
+type stagedVar interface {
+	INode
+	applySetDuringStabilization()
+	hasSetDuringStabilization() bool
+}
+
 type varIncr[T any] struct {
 	n                           *Node
 	setAt                       uint64
 	value                       T
 	setDuringStabilizationValue T
 	setDuringStabilization      bool
+	// setDuringStabilizationMu interlocks the staged value
+	// fields if the graph is concurrent.
+	setDuringStabilizationMu sync.Mutex
+	// equal is an optional function that is used to cut off
+	// recomputation if the value is equal to the changedValue.
+	equal func(T, T) bool
+	// changedValue is the value of the var when it last
+	// changed, or its initial value, and is only tracked if equal is set.
+	changedValue T
 }
 
 func (vn *varIncr[T]) Stale() bool {
@@ -56,35 +107,119 @@ func (vn *varIncr[T]) ShouldBeInvalidated() bool {
 }
 
 func (vn *varIncr[T]) Set(v T) {
+	vn.Update(func(_ T) T { return v })
+}
+
+func (vn *varIncr[T]) Update(fn func(T) T) {
 	graph := GraphForNode(vn)
-	if atomic.LoadInt32(&graph.status) == StatusStabilizing {
-		vn.setDuringStabilizationValue = v
+	if graph.concurrent {
+		graph.stateMu.Lock()
+	}
+	stale, notify := vn.assign(graph, fn)
+	if stale {
+		graph.setStale(vn)
+	}
+	handlers := graph.onSetStale
+	if graph.concurrent {
+		graph.stateMu.Unlock()
+	}
+	if notify {
+		for _, handler := range handlers {
+			handler.fn(vn)
+		}
+	}
+}
+
+// assign sets the var value to the result of a given function, either staging
+// the value until the current stabilization completes or applying it immediately.
+//
+// It returns if the var should be marked stale, and if the set handlers should be notified.
+func (vn *varIncr[T]) assign(graph *Graph, fn func(T) T) (stale, notify bool) {
+	vn.setDuringStabilizationMu.Lock()
+	// a staged value is applied after the update handlers run, so sets made while
+	// a value is staged are staged as well so that they're not overwritten.
+	if vn.setDuringStabilization || vn.shouldStageSet(graph) {
+		if vn.setDuringStabilization {
+			vn.setDuringStabilizationValue = fn(vn.setDuringStabilizationValue)
+		} else {
+			vn.setDuringStabilizationValue = fn(vn.value)
+		}
 		vn.setDuringStabilization = true
+		vn.setDuringStabilizationMu.Unlock()
 
 		graph.setDuringStabilizationMu.Lock()
-		graph.setDuringStabilization[vn.Node().id] = vn
+		graph.setDuringStabilization, graph.setDuringStabilizationIndex = addIndexed(graph.setDuringStabilization, graph.setDuringStabilizationIndex, stagedVar(vn))
 		graph.setDuringStabilizationMu.Unlock()
+		notify = true
 		return
 	}
-	vn.value = v
+	vn.setDuringStabilizationMu.Unlock()
+
+	vn.value = fn(vn.value)
 	if vn.n.isNecessary() {
-		graph.SetStale(vn)
+		stale = true
+		notify = true
+		return
+	}
+	// record the set so that the var is stale if it becomes
+	// necessary again with its recompute state intact, e.g.
+	// within a revived [BindKeyed] scope.
+	vn.setAt = graph.stabilizationNum
+	return
+}
+
+// shouldStageSet returns if a set should be staged until the
+// current stabilization completes.
+//
+// Concurrent graphs also stage sets while update handlers are
+// running as they may be called from other goroutines.
+func (vn *varIncr[T]) shouldStageSet(graph *Graph) bool {
+	status := atomic.LoadInt32(&graph.status)
+	if graph.concurrent {
+		return status != StatusNotStabilizing
 	}
+	return status == StatusStabilizing
 }
 
 func (vn *varIncr[T]) Node() *Node { return vn.n }
 
 func (vn *varIncr[T]) Value() T { return vn.value }
 
+func (vn *varIncr[T]) Cutoff(_ context.Context) (bool, error) {
+	if vn.equal == nil {
+		return false, nil
+	}
+	return vn.equal(vn.changedValue, vn.value), nil
+}
+
 func (vn *varIncr[T]) Stabilize(ctx context.Context) error {
+	vn.applySetDuringStabilization()
+	if vn.equal != nil {
+		vn.changedValue = vn.value
+	}
+	return nil
+}
+
+// hasSetDuringStabilization returns if a value is staged.
+func (vn *varIncr[T]) hasSetDuringStabilization() bool {
+	vn.setDuringStabilizationMu.Lock()
+	defer vn.setDuringStabilizationMu.Unlock()
+	return vn.setDuringStabilization
+}
+
+// applySetDuringStabilization applies the value staged
+// during stabilization if one was staged.
+func (vn *varIncr[T]) applySetDuringStabilization() {
+	if GraphForNode(vn).concurrent {
+		vn.setDuringStabilizationMu.Lock()
+		defer vn.setDuringStabilizationMu.Unlock()
+	}
 	if vn.setDuringStabilization {
 		var zero T
 		vn.value = vn.setDuringStabilizationValue
 		vn.setDuringStabilizationValue = zero
 		vn.setDuringStabilization = false
-		return nil
 	}
-	return nil
 }
 
 func (vn *varIncr[T]) String() string {
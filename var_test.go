@@ -87,6 +87,94 @@ func Test_Var_Set_duringStabilization_realistic(t *testing.T) {
 	testutil.Equal(t, "during-stab-done!", o.Value())
 }
 
+func Test_Var_Update(t *testing.T) {
+	ctx := testContext()
+	g := New()
+	v := Var(g, 1)
+	o := MustObserve(g, Map(g, v, func(a int) int { return a * 10 }))
+
+	_ = g.Stabilize(ctx)
+	testutil.Equal(t, 10, o.Value())
+
+	v.Update(func(a int) int { return a + 1 })
+	testutil.Equal(t, 2, v.Value())
+
+	_ = g.Stabilize(ctx)
+	testutil.Equal(t, 20, o.Value())
+}
+
+func Test_Var_Update_duringStabilization(t *testing.T) {
+	ctx := testContext()
+	g := New()
+	v := Var(g, 1)
+	m := Map(g, v, func(a int) int {
+		v.Update(func(b int) int { return b + 1 })
+		v.Update(func(b int) int { return b * 10 })
+		return a
+	})
+	o := MustObserve(g, m)
+
+	_ = g.Stabilize(ctx)
+	testutil.Equal(t, 1, o.Value())
+	testutil.Equal(t, 20, v.Value(), "the second update should compose with the staged value")
+}
+
+func Test_Var_Update_afterStagedSet(t *testing.T) {
+	ctx := testContext()
+	g := New()
+	v := Var(g, 1)
+	m := Map(g, v, func(a int) int {
+		if a == 1 {
+			v.Set(5)
+		}
+		return a
+	})
+	o := MustObserve(g, m)
+	o.OnUpdate(func(_ context.Context, _ int) {
+		// the value set during stabilization is still staged
+		// while the update handlers run.
+		v.Update(func(b int) int { return b + 1 })
+	})
+
+	_ = g.Stabilize(ctx)
+	testutil.Equal(t, 6, v.Value())
+
+	_ = g.Stabilize(ctx)
+	testutil.Equal(t, 6, o.Value())
+}
+
+func Test_VarWithEqual(t *testing.T) {
+	ctx := testContext()
+	g := New()
+	v := VarWithEqual(g, "foo", Equals)
+	var calls int
+	m := Map(g, v, func(vv string) string {
+		calls++
+		return vv + "-bar"
+	})
+	o := MustObserve(g, m)
+
+	err := g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "foo-bar", o.Value())
+	testutil.Equal(t, 1, calls)
+	changedAt := v.Node().changedAt
+
+	v.Set("foo")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "foo-bar", o.Value())
+	testutil.Equal(t, 1, calls, "setting an equal value should not recompute children")
+	testutil.Equal(t, changedAt, v.Node().changedAt)
+
+	v.Set("not-foo")
+	err = g.Stabilize(ctx)
+	testutil.NoError(t, err)
+	testutil.Equal(t, "not-foo-bar", o.Value())
+	testutil.Equal(t, 2, calls)
+	testutil.NotEqual(t, changedAt, v.Node().changedAt)
+}
+
 func Test_Var_ShouldBeInvalidated(t *testing.T) {
 	g := New()
 	v := Var(g, "foo")
@@ -46,6 +46,7 @@ func (w *watchIncr[A]) Parents() []INode {
 }
 
 func (w *watchIncr[A]) Value() A {
+	w.n.checkRead(w)
 	return w.value
 }
 
@@ -0,0 +1,90 @@
+package incr
+
+import (
+	"sync"
+	"time"
+)
+
+// workerPoolIdleTimeout is the time a worker pool goroutine
+// waits for work before it exits.
+const workerPoolIdleTimeout = 10 * time.Second
+
+// newWorkerPool returns a new worker pool with a given size.
+func newWorkerPool(size int) *workerPool {
+	if size < 1 {
+		size = 1
+	}
+	return &workerPool{
+		size: size,
+		work: make(chan func()),
+	}
+}
+
+// workerPool is a pool of goroutines that is kept by the graph
+// so that parallel stabilizations don't start a goroutine per node.
+//
+// Workers are started as work is submitted, up to the size of the pool, and
+// exit after they have been idle for [workerPoolIdleTimeout] so that a pool
+// that is no longer used doesn't hold onto goroutines.
+type workerPool struct {
+	size int
+	work chan func()
+
+	mu      sync.Mutex
+	workers int
+	waiting int
+}
+
+// submit runs a given function on a worker, blocking
+// until a worker is available if the pool is busy.
+func (wp *workerPool) submit(fn func()) {
+	select {
+	case wp.work <- fn:
+		return
+	default:
+	}
+
+	wp.mu.Lock()
+	if wp.workers < wp.size {
+		wp.workers++
+		wp.mu.Unlock()
+		go wp.worker(fn)
+		return
+	}
+	// workers won't exit while there are submitters waiting.
+	wp.waiting++
+	wp.mu.Unlock()
+
+	wp.work <- fn
+
+	wp.mu.Lock()
+	wp.waiting--
+	wp.mu.Unlock()
+}
+
+func (wp *workerPool) worker(fn func()) {
+	for fn != nil {
+		fn()
+		fn = wp.next()
+	}
+}
+
+func (wp *workerPool) next() func() {
+	idle := time.NewTimer(workerPoolIdleTimeout)
+	defer idle.Stop()
+	for {
+		select {
+		case fn := <-wp.work:
+			return fn
+		case <-idle.C:
+			wp.mu.Lock()
+			if wp.waiting == 0 {
+				wp.workers--
+				wp.mu.Unlock()
+				return nil
+			}
+			wp.mu.Unlock()
+			idle.Reset(workerPoolIdleTimeout)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package incr
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/wcharczuk/go-incr/testutil"
+)
+
+func Test_workerPool_submit(t *testing.T) {
+	wp := newWorkerPool(2)
+
+	var processed int32
+	wg := new(sync.WaitGroup)
+	wg.Add(16)
+	for x := 0; x < 16; x++ {
+		wp.submit(func() {
+			defer wg.Done()
+			atomic.AddInt32(&processed, 1)
+		})
+	}
+	wg.Wait()
+	testutil.Equal(t, 16, processed)
+
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+	testutil.Equal(t, true, wp.workers > 0)
+	testutil.Equal(t, true, wp.workers <= 2)
+	testutil.Equal(t, 0, wp.waiting)
+}
+
+func Test_workerPool_submit_blocksWhenBusy(t *testing.T) {
+	wp := newWorkerPool(1)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	wp.submit(func() {
+		close(started)
+		<-release
+	})
+	<-started
+
+	submitted := make(chan struct{})
+	ran := make(chan struct{})
+	go func() {
+		wp.submit(func() {
+			close(ran)
+		})
+		close(submitted)
+	}()
+
+	select {
+	case <-submitted:
+		t.Fatal("submit should block while the pool is busy")
+	default:
+	}
+	close(release)
+	<-submitted
+	<-ran
+
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+	testutil.Equal(t, 1, wp.workers)
+}
+
+func Test_newWorkerPool_minSize(t *testing.T) {
+	wp := newWorkerPool(0)
+	testutil.Equal(t, 1, wp.size)
+}